@@ -12,8 +12,9 @@ import (
 
 type changeNameScreen struct {
 	client         *client
-	newName        string
+	newName        []rune
 	newNameText    *ui.Text
+	errorText      *ui.Text
 	continueButton *ui.Button
 }
 
@@ -22,7 +23,7 @@ var _ screen = (*changeNameScreen)(nil)
 func newChangeNameScreen(client *client) *changeNameScreen {
 	screen := changeNameScreen{
 		client:  client,
-		newName: client.name,
+		newName: []rune(client.name),
 		newNameText: ui.NewText(ui.TextConfig{
 			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
 				return ui.CenteredPosition{X: width / 2, Y: height / 3}
@@ -31,6 +32,11 @@ func newChangeNameScreen(client *client) *changeNameScreen {
 			Colors: &ui.TitleColors,
 			Font:   rescources.RobotoTitleFont,
 		}),
+		errorText: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height/3 + 100}
+			}),
+		}),
 	}
 
 	screen.continueButton = ui.NewButton(ui.ButtonConfig{
@@ -39,26 +45,48 @@ func newChangeNameScreen(client *client) *changeNameScreen {
 		}),
 		Text: "Namen ändern",
 		Callback: func() {
+			newName := protocol.NormalizeName(string(screen.newName))
+			if reason, ok := protocol.ValidateName(newName); !ok {
+				screen.showRejection(reason)
+				return
+			}
+
+			// Der Name wird erst übernommen, wenn der Server ihn mit dem protocol.NameChangedPacket bestätigt
 			changeName, err := json.Marshal(protocol.ChangeNamePacket{
 				PacketName: protocol.ChangeNamePacketName,
-				NewName:    screen.newName,
+				NewName:    newName,
 			})
 			if err != nil {
 				panic(err)
 			}
 			client.SendPacket(changeName)
-
-			client.name = screen.newName
-
-			client.currentScreen = newTitleScreen(client)
 		},
 	})
 
 	return &screen
 }
 
+func nameRejectionText(reason protocol.NameRejectionReason) string {
+	switch reason {
+	case protocol.NameTooShort:
+		return "Der Name ist zu kurz"
+	case protocol.NameTooLong:
+		return "Der Name ist zu lang"
+	case protocol.NameInvalidCharacters:
+		return "Der Name enthält ungültige Zeichen"
+	case protocol.NameChangeInParty:
+		return "Der Name kann in einer Party nicht geändert werden"
+	default:
+		return "Der Name wurde abgelehnt"
+	}
+}
+
+func (c *changeNameScreen) showRejection(reason protocol.NameRejectionReason) {
+	c.errorText.Text = nameRejectionText(reason)
+}
+
 func (c *changeNameScreen) components() []ui.Component {
-	return []ui.Component{c.newNameText, c.continueButton}
+	return []ui.Component{c.newNameText, c.errorText, c.continueButton}
 }
 
 func (c *changeNameScreen) update() {
@@ -66,11 +94,15 @@ func (c *changeNameScreen) update() {
 		c.client.currentScreen = newTitleScreen(c.client)
 	}
 
-	c.newName = string(ebiten.AppendInputChars([]rune(c.newName)))
+	c.newName = ebiten.AppendInputChars(c.newName)
+	if len(c.newName) > protocol.MaxNameLength {
+		c.newName = c.newName[:protocol.MaxNameLength]
+	}
+	// Es wird immer ein ganzes Zeichen gelöscht, damit Umlaute nicht zerstört werden
 	if len(c.newName) != 0 && inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
 		c.newName = c.newName[:len(c.newName)-1]
 	}
-	c.newNameText.Text = c.newName
+	c.newNameText.Text = string(c.newName)
 
 	for _, component := range c.components() {
 		component.Update()
@@ -6,9 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net"
 	"sort"
-	"sync"
 
 	"github.com/Lama06/Oinky-Party/client/game"
 	"github.com/Lama06/Oinky-Party/protocol"
@@ -20,22 +18,30 @@ func StartClient() {
 }
 
 type client struct {
-	conn           net.Conn
-	send           chan []byte
-	receive        chan []byte
-	disconnected   chan struct{}
-	disconnectOnce sync.Once
-
-	name string
-	id   int32
-
-	inParty      bool
-	partyName    string
-	partyId      int32
-	partyPlayers map[int32]game.PartyPlayer
+	connection    *connection
+	serverAddress string
+	sessionToken  string // Damit wird die Sitzung nach einem Verbindungsabbruch fortgesetzt
+
+	name           string
+	id             int32
+	availableGames []protocol.GameTypeData
+
+	inParty         bool
+	partyName       string
+	partyId         int32
+	partyHost       int32
+	partyPlayers    map[int32]game.PartyPlayer
+	playlist        protocol.PlaylistData
+	playlistVersion int // Wird erhöht, wenn sich die Playlist ändert, damit Screens ihre Komponenten neu erstellen können
 
 	currentScreen screen
+	replayDir     string
 	currentGame   game.Game
+	gamePlayers   []int32
+	gameOptions   protocol.OptionValues
+	lastResult    *protocol.GameResultData // Das Ergebnis des letzten Spieles in der Party
+	rematch       protocol.RematchStatusPacket
+	pause         protocol.PauseStatusPacket
 }
 
 var _ game.Client = (*client)(nil)
@@ -43,25 +49,24 @@ var _ ebiten.Game = (*client)(nil)
 
 func newClient() *client {
 	return &client{
-		send:         make(chan []byte, 100),
-		receive:      make(chan []byte, 100),
-		disconnected: make(chan struct{}, 1),
 		partyPlayers: map[int32]game.PartyPlayer{},
 	}
 }
 
 func (c *client) start() {
-	serverAddress := flag.String("address", "localhost", "Server Address")
+	flag.StringVar(&c.serverAddress, "address", "localhost", "Server Address")
+	flag.StringVar(&c.replayDir, "replay-dir", "replays", "Der Ordner, in dem nach Wiederholungen gesucht wird")
 	flag.Parse()
 
 	log.SetFlags(log.Lshortfile | log.Ltime)
 	log.Println("client starting...")
 
-	err := c.connect(*serverAddress)
+	connection, err := connect(c.serverAddress)
 	if err != nil {
 		log.Println(fmt.Errorf("failed to connect to the server: %w", err))
 		return
 	}
+	c.connection = connection
 
 	ebiten.SetWindowTitle("Oinky Party")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
@@ -93,6 +98,36 @@ func (c *client) handlePacket(packet []byte) error {
 
 		c.id = welcome.YourId
 		c.name = welcome.YourName
+		c.sessionToken = welcome.SessionToken
+	case protocol.NameChangedPacketName:
+		var nameChanged protocol.NameChangedPacket
+		err := json.Unmarshal(packet, &nameChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		c.name = nameChanged.Name
+		if _, ok := c.currentScreen.(*changeNameScreen); ok {
+			c.currentScreen = newTitleScreen(c)
+		}
+	case protocol.NameRejectedPacketName:
+		var nameRejected protocol.NameRejectedPacket
+		err := json.Unmarshal(packet, &nameRejected)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if changeNameScreen, ok := c.currentScreen.(*changeNameScreen); ok {
+			changeNameScreen.showRejection(nameRejected.Reason)
+		}
+	case protocol.AvailableGamesPacketName:
+		var availableGames protocol.AvailableGamesPacket
+		err := json.Unmarshal(packet, &availableGames)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		c.availableGames = availableGames.Games
 	case protocol.YouJoinedPartyPacketName:
 		var youJoinedParty protocol.YouJoinedPartyPacket
 		err := json.Unmarshal(packet, &youJoinedParty)
@@ -107,11 +142,18 @@ func (c *client) handlePacket(packet []byte) error {
 		c.inParty = true
 		c.partyName = youJoinedParty.Party.Name
 		c.partyId = youJoinedParty.Party.Id
+		c.partyHost = youJoinedParty.Party.Host
+		c.lastResult = nil
+		c.rematch = protocol.RematchStatusPacket{}
+		c.playlist = youJoinedParty.Party.Playlist
+		c.playlistVersion++
 		c.partyPlayers = make(map[int32]game.PartyPlayer, len(youJoinedParty.Party.Players))
 		for _, player := range youJoinedParty.Party.Players {
 			c.partyPlayers[player.Id] = game.PartyPlayer{
 				Name: player.Name,
 				Id:   player.Id,
+				Team: player.Team,
+				Bot:  player.Bot,
 			}
 		}
 
@@ -127,12 +169,7 @@ func (c *client) handlePacket(packet []byte) error {
 			return errors.New("not in a party")
 		}
 
-		c.inParty = false
-		c.partyName = ""
-		c.partyId = 0
-		c.partyPlayers = nil
-
-		c.currentScreen = newTitleScreen(c)
+		c.leaveParty()
 	case protocol.PlayerJoinedPartyPacketName:
 		var playerJoinedParty protocol.PlayerJoinedPartyPacket
 		err := json.Unmarshal(packet, &playerJoinedParty)
@@ -147,6 +184,8 @@ func (c *client) handlePacket(packet []byte) error {
 		player := game.PartyPlayer{
 			Name: playerJoinedParty.Player.Name,
 			Id:   playerJoinedParty.Player.Id,
+			Team: playerJoinedParty.Player.Team,
+			Bot:  playerJoinedParty.Player.Bot,
 		}
 		c.partyPlayers[player.Id] = player
 	case protocol.PlayerLeftPartyPacketName:
@@ -161,6 +200,78 @@ func (c *client) handlePacket(packet []byte) error {
 		}
 
 		delete(c.partyPlayers, playerLeftParty.Id)
+	case protocol.TeamsChangedPacketName:
+		var teamsChanged protocol.TeamsChangedPacket
+		err := json.Unmarshal(packet, &teamsChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if !c.inParty {
+			return errors.New("received teams changed packet but client is not in a party")
+		}
+
+		for id, team := range teamsChanged.Teams {
+			player, ok := c.partyPlayers[id]
+			if !ok {
+				continue
+			}
+			player.Team = team
+			c.partyPlayers[id] = player
+		}
+	case protocol.HostChangedPacketName:
+		var hostChanged protocol.HostChangedPacket
+		err := json.Unmarshal(packet, &hostChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if !c.inParty {
+			return errors.New("received host changed packet but client is not in a party")
+		}
+
+		c.partyHost = hostChanged.Id
+	case protocol.PlaylistChangedPacketName:
+		var playlistChanged protocol.PlaylistChangedPacket
+		err := json.Unmarshal(packet, &playlistChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if !c.inParty {
+			return errors.New("received playlist changed packet but client is not in a party")
+		}
+
+		c.playlist = playlistChanged.Playlist
+		c.playlistVersion++
+	case protocol.StandingsPacketName:
+		var standings protocol.StandingsPacket
+		err := json.Unmarshal(packet, &standings)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if !c.inParty {
+			return errors.New("received standings packet but client is not in a party")
+		}
+
+		if c.currentGame != nil {
+			return errors.New("received standings packet but there is a game running")
+		}
+
+		c.currentScreen = newStandingsScreen(c, standings)
+	case protocol.RematchStatusPacketName:
+		var rematchStatus protocol.RematchStatusPacket
+		err := json.Unmarshal(packet, &rematchStatus)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if !c.inParty {
+			return errors.New("received rematch status packet but client is not in a party")
+		}
+
+		c.rematch = rematchStatus
 	case protocol.GameStartedPacketName:
 		var gameStarted protocol.GameStartedPacket
 		err := json.Unmarshal(packet, &gameStarted)
@@ -181,10 +292,56 @@ func (c *client) handlePacket(packet []byte) error {
 			return fmt.Errorf("unknown game type: %s", gameStarted.GameType)
 		}
 
+		c.gamePlayers = gameStarted.Players
+		c.gameOptions = gameStarted.Options
+		c.rematch = protocol.RematchStatusPacket{}
+		c.pause = protocol.PauseStatusPacket{}
+
 		newGame := gameType.Creator(c)
 		newGame.HandleGameStarted()
 		c.currentGame = newGame
 		c.currentScreen = newGameScreen(c)
+	case protocol.GameSnapshotPacketName:
+		var gameSnapshot protocol.GameSnapshotPacket
+		err := json.Unmarshal(packet, &gameSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if c.currentGame == nil {
+			return errors.New("received game snapshot packet but there is no game running")
+		}
+
+		snapshotGame, ok := c.currentGame.(game.SnapshotGame)
+		if !ok {
+			return errors.New("the current game does not support snapshots")
+		}
+
+		err = snapshotGame.ApplySnapshot(gameSnapshot.Snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to apply snapshot: %w", err)
+		}
+	case protocol.PauseStatusPacketName:
+		var pauseStatus protocol.PauseStatusPacket
+		err := json.Unmarshal(packet, &pauseStatus)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		if c.currentGame == nil {
+			return errors.New("received pause status packet but there is no game running")
+		}
+
+		wasPaused := c.pause.Paused
+		c.pause = pauseStatus
+
+		if pausableGame, ok := c.currentGame.(game.PausableGame); ok && wasPaused != pauseStatus.Paused {
+			if pauseStatus.Paused {
+				pausableGame.HandlePaused()
+			} else {
+				pausableGame.HandleResumed()
+			}
+		}
 	case protocol.GameEndedPacketName:
 		var gameEnded protocol.GameEndedPacket
 		err := json.Unmarshal(packet, &gameEnded)
@@ -200,9 +357,22 @@ func (c *client) handlePacket(packet []byte) error {
 			return errors.New("received game ended packet but there is no game running")
 		}
 
-		c.currentGame.HandleGameEnded()
+		endedGame := c.currentGame
+		endedGame.HandleGameEnded()
 		c.currentGame = nil
-		c.currentScreen = newPartyScreen(c)
+		c.gamePlayers = nil
+		c.gameOptions = nil
+		c.pause = protocol.PauseStatusPacket{}
+		if len(gameEnded.Result.Ranking) != 0 {
+			c.lastResult = &gameEnded.Result
+		}
+
+		if resultGame, ok := endedGame.(game.ResultGame); ok && len(gameEnded.Result.Ranking) != 0 {
+			resultGame.HandleResult(gameEnded.Result)
+			c.currentScreen = newGameResultScreen(c, resultGame)
+		} else {
+			c.currentScreen = newPartyScreen(c)
+		}
 	default:
 		if packetHandler, ok := c.currentScreen.(packetHandlerScreen); ok {
 			err := packetHandler.handlePacket(packet)
@@ -215,6 +385,57 @@ func (c *client) handlePacket(packet []byte) error {
 	return nil
 }
 
+// leaveParty setzt den Zustand der Party zurück, nachdem der Client sie verlassen hat.
+func (c *client) leaveParty() {
+	if c.currentGame != nil {
+		c.currentGame.HandleGameEnded()
+		c.currentGame = nil
+		c.gamePlayers = nil
+		c.gameOptions = nil
+		c.pause = protocol.PauseStatusPacket{}
+	}
+
+	c.inParty = false
+	c.partyName = ""
+	c.partyId = 0
+	c.partyHost = 0
+	c.lastResult = nil
+	c.rematch = protocol.RematchStatusPacket{}
+	c.partyPlayers = nil
+	c.playlist = protocol.PlaylistData{}
+	c.playlistVersion++
+
+	c.currentScreen = newTitleScreen(c)
+}
+
+// reconnect baut nach einem Verbindungsabbruch eine neue Verbindung auf und setzt die alte Sitzung fort.
+// Der Server lässt den Client dann wieder seiner Party beitreten.
+func (c *client) reconnect() error {
+	if c.sessionToken == "" {
+		return errors.New("there is no session to resume")
+	}
+
+	connection, err := connect(c.serverAddress)
+	if err != nil {
+		return fmt.Errorf("failed to reconnect: %w", err)
+	}
+	c.connection = connection
+	log.Println("reconnected to the server")
+
+	c.leaveParty()
+
+	resumeSession, err := json.Marshal(protocol.ResumeSessionPacket{
+		PacketName:   protocol.ResumeSessionPacketName,
+		SessionToken: c.sessionToken,
+	})
+	if err != nil {
+		panic(err)
+	}
+	c.SendPacket(resumeSession)
+
+	return nil
+}
+
 func (c *client) Name() string {
 	return c.name
 }
@@ -239,6 +460,53 @@ func (c *client) PartyPlayers() map[int32]game.PartyPlayer {
 	return partyPlayers
 }
 
+func (c *client) GamePlayers() map[int32]game.PartyPlayer {
+	gamePlayers := make(map[int32]game.PartyPlayer, len(c.gamePlayers))
+	for _, id := range c.gamePlayers {
+		if player, ok := c.partyPlayers[id]; ok {
+			gamePlayers[id] = player
+		}
+	}
+	return gamePlayers
+}
+
+func (c *client) Spectating() bool {
+	for _, id := range c.gamePlayers {
+		if id == c.id {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *client) GameOptions() protocol.OptionValues {
+	return c.gameOptions
+}
+
+// canVoteForRematch gibt an, ob der Client am letzten Spiel teilgenommen hat und noch für eine Revanche stimmen kann.
+func (c *client) canVoteForRematch() bool {
+	if !c.rematch.Available {
+		return false
+	}
+
+	for _, id := range c.rematch.Votes {
+		if id == c.id {
+			return false
+		}
+	}
+
+	for _, id := range c.rematch.Players {
+		if id == c.id {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *client) isHost() bool {
+	return c.inParty && c.partyHost == c.id
+}
+
 func (c *client) partyPlayersSorted() []game.PartyPlayer {
 	ids := make([]int32, 0, len(c.partyPlayers))
 	for id := range c.partyPlayers {
@@ -254,12 +522,16 @@ func (c *client) partyPlayersSorted() []game.PartyPlayer {
 }
 
 func (c *client) Update() error {
-	if len(c.disconnected) == 1 {
-		return errors.New("disconnected from the server")
+	if len(c.connection.disconnected) == 1 {
+		log.Println("disconnected from the server")
+		err := c.reconnect()
+		if err != nil {
+			return fmt.Errorf("disconnected from the server: %w", err)
+		}
 	}
 
-	for len(c.receive) != 0 {
-		packet := <-c.receive
+	for len(c.connection.receive) != 0 {
+		packet := <-c.connection.receive
 		err := c.handlePacket(packet)
 		if err != nil {
 			log.Println(fmt.Errorf("failed to handle packet from server: %w", err))
@@ -283,5 +555,8 @@ func (c *client) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHei
 	if c.currentGame != nil {
 		return c.currentGame.Layout(outsideWidth, outsideHeight)
 	}
+	if layoutScreen, ok := c.currentScreen.(layoutScreen); ok {
+		return layoutScreen.layout(outsideWidth, outsideHeight)
+	}
 	return outsideWidth, outsideHeight
 }
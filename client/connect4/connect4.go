@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
 	"image/color"
+	"strings"
 
 	"github.com/Lama06/Oinky-Party/client/game"
+	"github.com/Lama06/Oinky-Party/client/ui"
 	shared "github.com/Lama06/Oinky-Party/connect4"
 	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -15,41 +18,301 @@ import (
 	"golang.org/x/image/colornames"
 )
 
-const cellSize = 50
+const (
+	cellSize    = 50
+	holeRadius  = cellSize * 0.38
+	pieceRadius = cellSize * 0.44 // Etwas größer als die Löcher, damit der Rand der Steine vom Spielfeld verdeckt wird
+	minWidth    = 400             // Damit die Namen auch bei schmalen Spielfeldern Platz haben
 
-type board [shared.BoardWidth][shared.BoardHeight]shared.Cell
+	// Von oben nach unten: Die Namen der Spieler, die Reihe über dem Spielfeld, das Spielfeld und wer am Zug ist
+	bannerHeight = cellSize
+	boardY       = bannerHeight + cellSize
+
+	gravity = 0.04 // Um wie viele Zellen pro Tick ein fallender Stein schneller wird
+
+	ghostAlpha = 100 // Die Deckkraft des Steines, der über der Spalte unter der Maus angezeigt wird
+)
+
+var (
+	boardColor      = color.RGBA{R: 30, G: 80, B: 200, A: 255}
+	turnBannerColor = color.RGBA{R: 220, G: 220, B: 220, A: 255}
+	highlightColor  = colornames.White
+	popColor        = color.NRGBA{A: 90}
+
+	// pieceColors enthält die Farben der Steine, der Index ist die shared.Color
+	pieceColors = [shared.MaxPlayers]color.RGBA{
+		shared.RedColor:    colornames.Red,
+		shared.YellowColor: colornames.Gold,
+		shared.GreenColor:  colornames.Limegreen,
+		shared.BlueColor:   colornames.Darkviolet,
+	}
+)
+
+func pieceColor(c shared.Color) color.RGBA {
+	if int(c) >= len(pieceColors) {
+		return colornames.Gray
+	}
+	return pieceColors[c]
+}
+
+// board ist das Spielfeld. Die Größe erfährt der Client aus dem shared.StartPacket.
+type board struct {
+	rules shared.Rules
+	cells [][]shared.Cell // cells[x][y], die Reihe mit y = 0 ist oben
+}
+
+func newBoard(rules shared.Rules) *board {
+	cells := make([][]shared.Cell, rules.Width)
+	for x := range cells {
+		cells[x] = make([]shared.Cell, rules.Height)
+	}
+	return &board{
+		rules: rules,
+		cells: cells,
+	}
+}
+
+// landingRow gibt die Reihe zurück, in der ein Stein landet, der in die Spalte geworfen wird.
+func (b *board) landingRow(x int) (y int, ok bool) {
+	if x < 0 || x >= b.rules.Width {
+		return 0, false
+	}
+
+	for y := b.rules.Height - 1; y >= 0; y-- {
+		if b.cells[x][y] == shared.EmptyCell {
+			return y, true
+		}
+	}
+	return 0, false
+}
 
 func (b *board) place(color shared.Color, x int) {
-	y := shared.BoardHeight - 1
-	for b[x][y] != shared.EmptyCell {
-		y--
-		if y == -1 {
-			return
+	if y, ok := b.landingRow(x); ok {
+		b.cells[x][y] = color.ToCell()
+	}
+}
+
+// canPop gibt an, ob die Farbe bei Pop Out den untersten Stein der Spalte entfernen darf.
+func (b *board) canPop(color shared.Color, x int) bool {
+	return b.rules.PopOut && x >= 0 && x < b.rules.Width && b.cells[x][b.rules.Height-1] == color.ToCell()
+}
+
+// pop entfernt den untersten Stein der Spalte. Die Steine darüber rutschen nach unten.
+func (b *board) pop(x int) {
+	column := b.cells[x]
+	copy(column[1:], column[:len(column)-1])
+	column[0] = shared.EmptyCell
+}
+
+// newBoardImage zeichnet das Spielfeld mit Löchern, durch die man die Steine dahinter sieht.
+func newBoardImage(rules shared.Rules) *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, rules.Width*cellSize, rules.Height*cellSize))
+	for px := 0; px < rules.Width*cellSize; px++ {
+		for py := 0; py < rules.Height*cellSize; py++ {
+			dx := float64(px%cellSize) + 0.5 - cellSize/2
+			dy := float64(py%cellSize) + 0.5 - cellSize/2
+			if dx*dx+dy*dy > holeRadius*holeRadius {
+				img.Set(px, py, boardColor)
+			}
 		}
 	}
-	b[x][y] = color.ToCell()
+	return ebiten.NewImageFromImage(img)
+}
+
+// fallingPiece ist ein Stein, der gerade in das Spielfeld fällt. Er steht bereits im board, wird aber bis zur
+// Landung an seiner aktuellen Höhe gezeichnet.
+type fallingPiece struct {
+	x, y     int // Die Zelle, in der der Stein landet
+	color    shared.Color
+	row      float64 // Die aktuelle Höhe in Zellen. -1 ist die Reihe über dem Spielfeld.
+	velocity float64
 }
 
 type impl struct {
-	client game.Client
-	board  *board
+	client         game.Client
+	board          *board
+	boardImage     *ebiten.Image // nil, bis das Spielfeld das erste Mal gezeichnet wird
+	players        []int32       // Die IDs der Spieler, der Index ist ihre Farbe
+	names          []string      // Die Namen bleiben nach dem Ende des Spieles erhalten
+	nameTexts      []*ui.Text
+	eliminated     map[shared.Color]bool
+	turnIndicator  *game.TurnIndicator
+	falling        *fallingPiece
+	result         *protocol.GameResultData // nil, solange das Spiel läuft
+	winningCells   []shared.Position
+	resultText     *ui.Text
+	moves          []shared.Move
+	view           int    // Nach dem Ende des Spieles: Wie viele Züge in der angezeigten Stellung gemacht wurden
+	viewBoard      *board // Die Stellung nach view Zügen, nil, wenn die aktuelle Stellung angezeigt wird
+	movesTitle     *ui.Text
+	moveText       *ui.Text // Wird für jede Zeile der Zugliste neu beschriftet
+	notation       string   // Nach dem Ende des Spieles: Die Notation aller Züge
+	notationText   *ui.Text
+	notationStatus string // Ob die Notation gespeichert wurde, leer, bis S gedrückt wird
 }
 
-var _ game.Game = (*impl)(nil)
+var _ game.SnapshotGame = (*impl)(nil)
+var _ game.ResultGame = (*impl)(nil)
 
 func create(client game.Client) game.Game {
-	return &impl{
+	i := &impl{
 		client: client,
-		board:  &board{},
+		// Bis zum shared.StartPacket wird ein Spielfeld mit der Standardgröße angezeigt
+		board:      newBoard(shared.RulesFromOptions(shared.Options.Defaults())),
+		eliminated: map[shared.Color]bool{},
 	}
+
+	bottom := ui.DynamicPosition(func(_, _ int) ui.Position {
+		return ui.CenteredPosition{X: i.width() / 2, Y: boardY + i.board.rules.Height*cellSize + cellSize/2}
+	})
+	i.turnIndicator = game.NewTurnIndicator(client, bottom)
+	// Nach dem Ende des Spieles steht dort stattdessen die Notation
+	i.notationText = ui.NewText(ui.TextConfig{Pos: bottom})
+	// Nach dem Ende des Spieles wird das Ergebnis in der Reihe über dem Spielfeld angezeigt
+	i.resultText = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(_, _ int) ui.Position {
+			return ui.CenteredPosition{X: i.width() / 2, Y: boardY - cellSize/2}
+		}),
+	})
+
+	i.movesTitle = ui.NewText(ui.TextConfig{})
+	i.moveText = ui.NewText(ui.TextConfig{})
+
+	return i
 }
 
 var _ game.Creator = create
 
+// width gibt die Breite des Bereiches mit dem Spielfeld zurück. Rechts daneben liegt die Zugliste.
+func (i *impl) width() int {
+	if width := i.board.rules.Width * cellSize; width > minWidth {
+		return width
+	}
+	return minWidth
+}
+
+// boardX gibt die X-Koordinate zurück, an der das Spielfeld beginnt. Es wird waagerecht zentriert.
+func (i *impl) boardX() int {
+	return (i.width() - i.board.rules.Width*cellSize) / 2
+}
+
+// bannerWidth gibt die Breite des Abschnittes im Banner zurück, in dem der Name eines Spielers steht.
+func (i *impl) bannerWidth() int {
+	if len(i.players) == 0 {
+		return i.width()
+	}
+	return i.width() / len(i.players)
+}
+
+// bannerX gibt die X-Koordinate zurück, an der der Abschnitt des Banners mit dem Namen des Spielers beginnt.
+func (i *impl) bannerX(c shared.Color) int {
+	return int(c) * i.bannerWidth()
+}
+
+func (i *impl) setRules(rules shared.Rules) {
+	i.board = newBoard(rules)
+	i.boardImage = nil
+	i.falling = nil
+	i.moves = nil
+	i.viewBoard = nil
+	i.notation = ""
+	i.notationStatus = ""
+}
+
+func (i *impl) setPlayers(ids []int32) {
+	i.players = ids
+	i.names = make([]string, len(ids))
+	i.nameTexts = make([]*ui.Text, len(ids))
+
+	players := i.client.GamePlayers()
+	for c, id := range ids {
+		name := "?"
+		if player, ok := players[id]; ok {
+			name = player.Name
+		}
+		if id == i.client.Id() {
+			name += " (Du)"
+		}
+		i.names[c] = name
+
+		c := shared.Color(c)
+		i.nameTexts[c] = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(_, _ int) ui.Position {
+				return ui.CenteredPosition{X: i.bannerX(c) + i.bannerWidth()/2 + cellSize/4, Y: bannerHeight / 2}
+			}),
+			Text: name,
+		})
+	}
+}
+
+// myColor gibt die Farbe des Clients zurück. ok ist false, wenn der Client nicht mitspielt.
+func (i *impl) myColor() (c shared.Color, ok bool) {
+	for c, id := range i.players {
+		if id == i.client.Id() {
+			return shared.Color(c), !i.client.Spectating() && !i.eliminated[shared.Color(c)]
+		}
+	}
+	return shared.RedColor, false
+}
+
+// nameOf gibt den Namen des Spielers mit der ID zurück.
+func (i *impl) nameOf(id int32) string {
+	for c, player := range i.players {
+		if player == id {
+			return i.names[c]
+		}
+	}
+	return "?"
+}
+
 func (i *impl) HandleGameStarted() {}
 
 func (i *impl) HandleGameEnded() {}
 
+func (i *impl) HandleResult(result protocol.GameResultData) {
+	i.result = &result
+
+	// Ohne Details wurde das Spiel zum Beispiel durch Aufgeben oder Verlassen beendet
+	var details shared.ResultDetails
+	if len(result.Details) != 0 && json.Unmarshal(result.Details, &details) == nil {
+		if !details.Draw {
+			i.winningCells = details.Cells
+		}
+		// Die Züge des Servers sind vollständig, auch wenn der Client erst während des Spieles beigetreten ist
+		if len(details.Moves) != 0 {
+			i.moves = details.Moves
+		}
+		i.notation = details.Notation
+	}
+	if i.notation == "" {
+		i.notation = shared.FormatNotation(i.moves)
+	}
+	i.showMove(len(i.moves))
+
+	var winners []int32
+	if len(result.Ranking) != 0 {
+		winners = result.Ranking[0]
+	}
+
+	switch {
+	case details.Draw:
+		i.resultText.Text = "Unentschieden"
+	case len(winners) == 0:
+		i.resultText.Text = "Das Spiel ist vorbei"
+	case len(winners) == 1 && winners[0] == i.client.Id() && !i.client.Spectating():
+		i.resultText.Text = "Du hast gewonnen"
+	case len(winners) == 1:
+		i.resultText.Text = i.nameOf(winners[0]) + " hat gewonnen"
+	default:
+		// Nach einem Pop Out können mehrere Spieler gemeinsam gewinnen
+		names := make([]string, len(winners))
+		for j, winner := range winners {
+			names[j] = i.nameOf(winner)
+		}
+		i.resultText.Text = strings.Join(names, " und ") + " haben gewonnen"
+	}
+}
+
 func (i *impl) HandlePacket(data []byte) error {
 	packetName, err := protocol.GetPacketName(data)
 	if err != nil {
@@ -57,6 +320,22 @@ func (i *impl) HandlePacket(data []byte) error {
 	}
 
 	switch packetName {
+	case shared.StartPacketName:
+		var start shared.StartPacket
+		err := json.Unmarshal(data, &start)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+		if !start.Rules.Valid() || len(start.Players) != start.Rules.Players {
+			return fmt.Errorf("invalid rules: %+v", start.Rules)
+		}
+
+		// Wer am Zug ist, erfährt der Client aus dem protocol.TurnChangedPacket
+		i.setRules(start.Rules)
+		i.setPlayers(start.Players)
+		return nil
+	case protocol.TurnChangedPacketName:
+		return i.turnIndicator.HandleTurnChangedPacket(data)
 	case shared.PlayerPlacedPacketName:
 		var playerPlaced shared.PlayerPlacedPacket
 		err := json.Unmarshal(data, &playerPlaced)
@@ -64,37 +343,205 @@ func (i *impl) HandlePacket(data []byte) error {
 			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
 
-		i.board.place(playerPlaced.Player, int(playerPlaced.X))
+		x := int(playerPlaced.X)
+		y, ok := i.board.landingRow(x)
+		if !ok {
+			return fmt.Errorf("cannot place in column: %d", x)
+		}
+		i.board.place(playerPlaced.Player, x)
+		i.moves = append(i.moves, shared.Move{Player: playerPlaced.Player, X: x})
+		// Ein Stein, der noch fällt, landet sofort
+		i.falling = &fallingPiece{
+			x:     x,
+			y:     y,
+			color: playerPlaced.Player,
+			row:   -1,
+		}
+		return nil
+	case shared.PlayerPoppedPacketName:
+		var playerPopped shared.PlayerPoppedPacket
+		err := json.Unmarshal(data, &playerPopped)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		x := int(playerPopped.X)
+		if !i.board.canPop(playerPopped.Player, x) {
+			return fmt.Errorf("cannot pop in column: %d", x)
+		}
+		i.falling = nil
+		i.board.pop(x)
+		i.moves = append(i.moves, shared.Move{Player: playerPopped.Player, X: x, Pop: true})
+		return nil
+	case shared.PlayerEliminatedPacketName:
+		var playerEliminated shared.PlayerEliminatedPacket
+		err := json.Unmarshal(data, &playerEliminated)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		i.eliminated[playerEliminated.Player] = true
 		return nil
 	default:
 		return errors.New("unknown packet name")
 	}
 }
 
+func (i *impl) ApplySnapshot(data []byte) error {
+	var snapshot shared.Snapshot
+	err := json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	if !snapshot.Rules.Valid() || len(snapshot.Board) != snapshot.Rules.Width ||
+		len(snapshot.Players) != snapshot.Rules.Players {
+		return fmt.Errorf("invalid board size: %+v", snapshot.Rules)
+	}
+	for _, column := range snapshot.Board {
+		if len(column) != snapshot.Rules.Height {
+			return fmt.Errorf("invalid board size: %+v", snapshot.Rules)
+		}
+	}
+
+	i.setRules(snapshot.Rules)
+	i.board.cells = snapshot.Board
+	i.moves = snapshot.Moves
+	i.setPlayers(snapshot.Players)
+	for _, c := range snapshot.Eliminated {
+		i.eliminated[c] = true
+	}
+	i.turnIndicator.SetTurn(snapshot.Turn)
+	return nil
+}
+
+// cellCenter gibt die Mitte der Zelle auf dem Bildschirm zurück. Die Reihe -1 liegt über dem Spielfeld.
+func (i *impl) cellCenter(x int, row float64) (float64, float64) {
+	return float64(i.boardX()+x*cellSize) + cellSize/2, boardY + row*cellSize + cellSize/2
+}
+
+// hoveredColumn gibt die Spalte zurück, über der sich die Maus befindet.
+func (i *impl) hoveredColumn() (x int, ok bool) {
+	mouseX, _ := ebiten.CursorPosition()
+	if mouseX < i.boardX() {
+		return 0, false
+	}
+	x = (mouseX - i.boardX()) / cellSize
+	return x, x < i.board.rules.Width
+}
+
+// canPlace gibt an, ob der Client gerade einen Stein setzen darf.
+func (i *impl) canPlace() bool {
+	_, playing := i.myColor()
+	return playing && i.result == nil && i.turnIndicator.IsMyTurn()
+}
+
+func (i *impl) drawBanner(screen *ebiten.Image) {
+	current, ok := i.turnIndicator.Current()
+	for c, id := range i.players {
+		c := shared.Color(c)
+		x := float64(i.bannerX(c))
+
+		if i.result == nil && ok && current == id {
+			ebitenutil.DrawRect(screen, x, 0, float64(i.bannerWidth()), bannerHeight, turnBannerColor)
+		}
+		clr := pieceColor(c)
+		if i.eliminated[c] {
+			clr = colornames.Lightgray
+		}
+		ebitenutil.DrawCircle(screen, x+cellSize/2, bannerHeight/2, holeRadius, clr)
+		i.nameTexts[c].Draw(screen)
+	}
+}
+
 func (i *impl) Draw(screen *ebiten.Image) {
 	screen.Fill(colornames.White)
 
-	for x := 0; x < shared.BoardWidth; x++ {
-		for y := 0; y < shared.BoardHeight; y++ {
-			var clr color.Color
-			switch i.board[x][y] {
-			case shared.EmptyCell:
+	i.drawBanner(screen)
+	i.drawMoves(screen)
+	board := i.shownBoard()
+
+	if x, ok := i.hoveredColumn(); ok && i.canPlace() {
+		if _, free := i.board.landingRow(x); free {
+			myColor, _ := i.myColor()
+			clr := pieceColor(myColor)
+			ghost := color.NRGBA{R: clr.R, G: clr.G, B: clr.B, A: ghostAlpha}
+			centerX, centerY := i.cellCenter(x, -1)
+			ebitenutil.DrawCircle(screen, centerX, centerY, pieceRadius, ghost)
+		}
+	}
+
+	for x := 0; x < i.board.rules.Width; x++ {
+		for y := 0; y < i.board.rules.Height; y++ {
+			cell := board.cells[x][y]
+			if cell == shared.EmptyCell || (i.falling != nil && i.falling.x == x && i.falling.y == y) {
 				continue
-			case shared.RedCell:
-				clr = colornames.Red
-			case shared.YellowCell:
-				clr = colornames.Yellow
 			}
-			ebitenutil.DrawRect(screen, float64(x*cellSize), float64(y*cellSize), cellSize, cellSize, clr)
+			centerX, centerY := i.cellCenter(x, float64(y))
+			ebitenutil.DrawCircle(screen, centerX, centerY, pieceRadius, pieceColor(cell.ToColor()))
+		}
+	}
+
+	if i.falling != nil {
+		centerX, centerY := i.cellCenter(i.falling.x, i.falling.row)
+		ebitenutil.DrawCircle(screen, centerX, centerY, pieceRadius, pieceColor(i.falling.color))
+	}
+
+	if i.boardImage == nil {
+		i.boardImage = newBoardImage(i.board.rules)
+	}
+	var options ebiten.DrawImageOptions
+	options.GeoM.Translate(float64(i.boardX()), boardY)
+	screen.DrawImage(i.boardImage, &options)
+
+	// Bei Pop Out wird der eigene Stein markiert, der mit einem Rechtsklick entfernt werden kann
+	if x, ok := i.hoveredColumn(); ok && i.canPlace() {
+		if myColor, _ := i.myColor(); i.board.canPop(myColor, x) {
+			centerX, centerY := i.cellCenter(x, float64(i.board.rules.Height-1))
+			ebitenutil.DrawCircle(screen, centerX, centerY, pieceRadius/3, popColor)
 		}
 	}
+
+	// Die Steine, mit denen gewonnen wurde, werden markiert, sobald sie gelandet sind und die letzte Stellung
+	// angezeigt wird
+	if i.falling == nil && i.viewBoard == nil {
+		for _, pos := range i.winningCells {
+			centerX, centerY := i.cellCenter(pos.X, float64(pos.Y))
+			ebitenutil.DrawCircle(screen, centerX, centerY, pieceRadius/3, highlightColor)
+		}
+	}
+
+	if i.result != nil {
+		i.resultText.Draw(screen)
+		i.drawNotation(screen)
+	} else {
+		i.turnIndicator.Draw(screen)
+	}
 }
 
 func (i *impl) Update() {
+	i.turnIndicator.Update()
+	i.updateMoves()
+	i.updateNotation()
+
+	if i.falling != nil {
+		i.falling.velocity += gravity
+		i.falling.row += i.falling.velocity
+		if i.falling.row >= float64(i.falling.y) {
+			i.falling = nil
+		}
+	}
+
+	if !i.canPlace() {
+		return
+	}
+
 	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		mouseX, _ := ebiten.CursorPosition()
-		x := mouseX / cellSize
-		if x < 0 || x > shared.BoardWidth-1 {
+		x, ok := i.hoveredColumn()
+		if !ok {
+			return
+		}
+		if _, free := i.board.landingRow(x); !free {
 			return
 		}
 
@@ -107,14 +554,47 @@ func (i *impl) Update() {
 		}
 		i.client.SendPacket(place)
 	}
+
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) {
+		x, ok := i.hoveredColumn()
+		if !ok {
+			return
+		}
+		if myColor, _ := i.myColor(); !i.board.canPop(myColor, x) {
+			return
+		}
+
+		pop, err := json.Marshal(shared.PopPacket{
+			PacketName: shared.PopPacketName,
+			X:          int32(x),
+		})
+		if err != nil {
+			panic(err)
+		}
+		i.client.SendPacket(pop)
+	}
 }
 
 func (i *impl) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return shared.BoardWidth * cellSize, shared.BoardHeight * cellSize
+	// Unter dem Spielfeld wird angezeigt, wer am Zug ist, und nach dem Ende des Spieles die Notation
+	return i.width() + movesWidth, boardY + (i.board.rules.Height+1)*cellSize
+}
+
+// checkOptions meldet, wenn auf dem gewählten Spielfeld keine Reihe mit der gewählten Länge gebildet werden kann. Der
+// Server würde das Spiel sonst nicht starten.
+func checkOptions(options protocol.OptionValues) string {
+	rules := shared.RulesFromOptions(options)
+	if rules.Valid() {
+		return ""
+	}
+	return fmt.Sprintf("Eine Reihe aus %d Steinen passt nicht auf ein %dx%d großes Spielfeld",
+		rules.WinningLength, rules.Width, rules.Height)
 }
 
 var Type = game.Type{
-	Creator:     create,
-	Name:        shared.Name,
-	DisplayName: "Vier Gewinnt",
+	Creator:      create,
+	Name:         shared.Name,
+	DisplayName:  "Vier Gewinnt",
+	Version:      shared.Version,
+	CheckOptions: checkOptions,
 }
@@ -0,0 +1,136 @@
+package connect4
+
+import (
+	"strconv"
+
+	"github.com/Lama06/Oinky-Party/client/ui"
+	shared "github.com/Lama06/Oinky-Party/connect4"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/colornames"
+)
+
+const (
+	movesWidth      = 3 * cellSize // Die Breite der Zugliste rechts neben dem Spielfeld
+	moveLineHeight  = 30
+	movesHintHeight = 30 // Unten bleibt Platz für den Hinweis des Ergebnisbildschirmes
+)
+
+// movesX gibt die X-Koordinate zurück, an der die Zugliste beginnt.
+func (i *impl) movesX() int {
+	return i.width()
+}
+
+// maxMoveLines gibt zurück, wie viele Züge gleichzeitig in der Liste angezeigt werden.
+func (i *impl) maxMoveLines() int {
+	_, height := i.Layout(0, 0)
+	return (height - bannerHeight - movesHintHeight) / moveLineHeight
+}
+
+// firstMoveLine gibt den Index des obersten Zuges in der Liste zurück. Die Liste folgt dem letzten oder dem gerade
+// angezeigten Zug.
+func (i *impl) firstMoveLine() int {
+	last := len(i.moves)
+	if i.result != nil {
+		last = i.view
+	}
+	if first := last - i.maxMoveLines(); first > 0 {
+		return first
+	}
+	return 0
+}
+
+// moveLineY gibt die Y-Koordinate zurück, an der die Zeile des Zuges beginnt.
+func (i *impl) moveLineY(move int) int {
+	return bannerHeight + (move-i.firstMoveLine())*moveLineHeight
+}
+
+// showMove zeigt nach dem Ende des Spieles die Stellung nach den ersten view Zügen an.
+func (i *impl) showMove(view int) {
+	if view < 0 {
+		view = 0
+	}
+	if view > len(i.moves) {
+		view = len(i.moves)
+	}
+	i.view = view
+	i.falling = nil
+
+	if view == len(i.moves) {
+		i.viewBoard = nil
+		return
+	}
+
+	i.viewBoard = newBoard(i.board.rules)
+	for _, move := range i.moves[:view] {
+		if move.Pop {
+			i.viewBoard.pop(move.X)
+		} else {
+			i.viewBoard.place(move.Player, move.X)
+		}
+	}
+}
+
+// shownBoard gibt das Spielfeld zurück, das gerade angezeigt wird.
+func (i *impl) shownBoard() *board {
+	if i.viewBoard != nil {
+		return i.viewBoard
+	}
+	return i.board
+}
+
+// updateMoves lässt den Spieler nach dem Ende des Spieles mit A und D oder durch Klicken auf die Liste durch die
+// Züge gehen.
+func (i *impl) updateMoves() {
+	if i.result == nil {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		i.showMove(i.view - 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		i.showMove(i.view + 1)
+	}
+
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		mouseX, mouseY := ebiten.CursorPosition()
+		if mouseX < i.movesX() || mouseY < bannerHeight {
+			return
+		}
+		move := i.firstMoveLine() + (mouseY-bannerHeight)/moveLineHeight
+		if move < len(i.moves) && move-i.firstMoveLine() < i.maxMoveLines() {
+			i.showMove(move + 1)
+		}
+	}
+}
+
+func (i *impl) drawMoves(screen *ebiten.Image) {
+	x := i.movesX()
+	_, height := i.Layout(0, 0)
+	ebitenutil.DrawRect(screen, float64(x), 0, movesWidth, float64(height), turnBannerColor)
+
+	i.movesTitle.Text = "Züge"
+	if i.result != nil {
+		i.movesTitle.Text = "Züge (A/D)"
+	}
+	i.movesTitle.Pos = ui.CenteredPosition{X: x + movesWidth/2, Y: bannerHeight / 2}
+	i.movesTitle.Draw(screen)
+
+	first := i.firstMoveLine()
+	for j := first; j < len(i.moves) && j-first < i.maxMoveLines(); j++ {
+		move := i.moves[j]
+		y := i.moveLineY(j)
+
+		// Nach dem Ende des Spieles wird der Zug markiert, nach dem die Stellung angezeigt wird
+		if i.result != nil && j == i.view-1 {
+			ebitenutil.DrawRect(screen, float64(x), float64(y), movesWidth, moveLineHeight, colornames.White)
+		}
+
+		ebitenutil.DrawCircle(screen, float64(x)+moveLineHeight/2, float64(y)+moveLineHeight/2, moveLineHeight/3, pieceColor(move.Player))
+		i.moveText.Text = strconv.Itoa(j+1) + ". " + shared.FormatMove(move)
+		i.moveText.Pos = ui.TopLeftCornerPosition{X: x + moveLineHeight + 5, Y: y + 5}
+		i.moveText.Draw(screen)
+	}
+}
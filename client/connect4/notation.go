@@ -0,0 +1,60 @@
+package connect4
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/font"
+)
+
+const saveNotationHint = " (S: Speichern)"
+
+// notationFileName gibt den Namen der Datei zurück, in der die Notation gespeichert wird. Die Datei kann mit
+// "connect4-ai -position" analysiert werden.
+func notationFileName() string {
+	return fmt.Sprintf("vier-gewinnt-%s.txt", time.Now().Format("2006-01-02-15-04-05"))
+}
+
+// updateNotation speichert nach dem Ende des Spieles die Notation, wenn S gedrückt wird.
+func (i *impl) updateNotation() {
+	if i.result == nil || i.notation == "" || !inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		return
+	}
+
+	fileName := notationFileName()
+	if err := os.WriteFile(fileName, []byte(i.notation+"\n"), 0o644); err != nil {
+		i.notationStatus = "Speichern fehlgeschlagen"
+		return
+	}
+	i.notationStatus = "Gespeichert in " + fileName
+}
+
+// fitText kürzt den Text am Ende, bis er mit dem Zusatz nicht breiter als width ist.
+func fitText(face font.Face, s, suffix string, width int) string {
+	if font.MeasureString(face, s+suffix).Ceil() <= width {
+		return s + suffix
+	}
+
+	runes := []rune(s)
+	for len(runes) != 0 && font.MeasureString(face, string(runes)+"…"+suffix).Ceil() > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…" + suffix
+}
+
+// drawNotation zeigt nach dem Ende des Spieles die Notation in der Reihe unter dem Spielfeld an.
+func (i *impl) drawNotation(screen *ebiten.Image) {
+	switch {
+	case i.notationStatus != "":
+		i.notationText.Text = fitText(rescources.RobotoNormalFont, i.notationStatus, "", i.width()-cellSize)
+	case i.notation != "":
+		i.notationText.Text = fitText(rescources.RobotoNormalFont, i.notation, saveNotationHint, i.width()-cellSize)
+	default:
+		return
+	}
+	i.notationText.Draw(screen)
+}
@@ -131,8 +131,8 @@ type obstacle struct {
 	clientPosX      float64
 }
 
-func (o *obstacle) clientTick(delta float64) {
-	o.clientPosX = o.serverPosX + shared.ObstacleSpeed*delta
+func (o *obstacle) clientTick(speed float64, delta float64) {
+	o.clientPosX = o.serverPosX + speed*delta
 }
 
 func addObstacleTexture(obstacleImage *ebiten.Image) {
@@ -182,7 +182,7 @@ type impl struct {
 	debugModeEnabled bool
 }
 
-var _ game.Game = (*impl)(nil)
+var _ game.PausableGame = (*impl)(nil)
 
 func create(client game.Client) game.Game {
 	return &impl{
@@ -204,9 +204,9 @@ func create(client game.Client) game.Game {
 var _ game.Creator = create
 
 func (i *impl) HandleGameStarted() {
-	partyPlayers := i.client.PartyPlayers()
-	i.players = make(map[int32]*player, len(partyPlayers))
-	for id := range partyPlayers {
+	gamePlayers := i.client.GamePlayers()
+	i.players = make(map[int32]*player, len(gamePlayers))
+	for id := range gamePlayers {
 		i.players[id] = &player{
 			id:           id,
 			serverPosY:   shared.OinkyStartPosY,
@@ -220,6 +220,14 @@ func (i *impl) HandleGameStarted() {
 
 func (i *impl) HandleGameEnded() {}
 
+func (i *impl) HandlePaused() {}
+
+// HandleResumed verhindert, dass die Vögel und Hindernisse nach der Pause anhand der Zeit seit dem letzten
+// UpdatePacket vorausberechnet werden.
+func (i *impl) HandleResumed() {
+	i.lastTickTime = time.Now().UnixMilli()
+}
+
 func (i *impl) HandlePacket(packet []byte) error {
 	packetName, err := protocol.GetPacketName(packet)
 	if err != nil {
@@ -308,8 +316,9 @@ func (i *impl) Update() {
 
 	delta := i.delta()
 
+	obstacleSpeed := shared.ObstacleSpeed(i.client.GameOptions())
 	for _, obstacle := range i.obstacles {
-		obstacle.clientTick(delta)
+		obstacle.clientTick(obstacleSpeed, delta)
 	}
 
 	for _, player := range i.players {
@@ -338,4 +347,5 @@ var Type = game.Type{
 	Creator:     create,
 	Name:        shared.Name,
 	DisplayName: "Flappy Oinky",
+	Version:     shared.Version,
 }
@@ -1,11 +1,20 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+)
 
+// Type beschreibt, wie der Client ein Spiel darstellt. Welche Spiele verfügbar sind und welche Einstellungen sie
+// haben, teilt der Server mit.
 type Type struct {
 	Creator     Creator
 	Name        string
 	DisplayName string
+	Version     int32
+	// CheckOptions gibt eine Meldung für den Spieler zurück, wenn die Einstellungen nicht zusammenpassen, und sonst
+	// einen leeren String. Kann nil sein.
+	CheckOptions func(options protocol.OptionValues) string
 }
 
 type Creator func(client Client) Game
@@ -13,6 +22,8 @@ type Creator func(client Client) Game
 type PartyPlayer struct {
 	Name string
 	Id   int32
+	Team int32
+	Bot  bool
 }
 
 type Client interface {
@@ -26,6 +37,15 @@ type Client interface {
 
 	PartyPlayers() map[int32]PartyPlayer
 
+	// GamePlayers gibt die Spieler zurück, die am aktuellen Spiel teilnehmen.
+	GamePlayers() map[int32]PartyPlayer
+
+	// Spectating gibt an, ob der Client beim aktuellen Spiel nur zuschaut.
+	Spectating() bool
+
+	// GameOptions gibt die Einstellungen des aktuellen Spieles zurück.
+	GameOptions() protocol.OptionValues
+
 	SendPacket(packet []byte)
 }
 
@@ -42,3 +62,32 @@ type Game interface {
 
 	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
 }
+
+// PausableGame kann von Spielen implementiert werden, die beim Pausieren oder Fortsetzen etwas tun müssen.
+// Während das Spiel pausiert ist, wird Update nicht aufgerufen.
+type PausableGame interface {
+	Game
+
+	HandlePaused()
+
+	HandleResumed()
+}
+
+// ResultGame kann von Spielen implementiert werden, die das Ergebnis am Ende des Spieles selbst anzeigen. Der Client
+// zeigt das Spiel dann weiter an, bis der Spieler es verlässt.
+type ResultGame interface {
+	Game
+
+	// HandleResult wird nach HandleGameEnded aufgerufen, wenn das Spiel nicht abgebrochen wurde. Danach erhält das
+	// Spiel keine Packets mehr.
+	HandleResult(result protocol.GameResultData)
+}
+
+// SnapshotGame kann von Spielen implementiert werden, die den Zustand eines laufenden Spieles übernehmen können,
+// wenn der Client während des Spieles dazukommt.
+type SnapshotGame interface {
+	Game
+
+	// ApplySnapshot wird direkt nach HandleGameStarted mit dem Inhalt des protocol.GameSnapshotPacket aufgerufen.
+	ApplySnapshot(snapshot []byte) error
+}
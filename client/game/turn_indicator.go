@@ -0,0 +1,140 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TurnIndicator zeigt an, welcher Spieler in einem rundenbasierten Spiel an der Reihe ist und wie viel Zeit ihm
+// noch bleibt. Die Spiele leiten das protocol.TurnChangedPacket an HandleTurnChangedPacket weiter.
+type TurnIndicator struct {
+	client     Client
+	turn       protocol.TurnChangedPacket
+	hasTurn    bool
+	receivedAt time.Time
+	text       *ui.Text
+}
+
+var _ ui.Component = (*TurnIndicator)(nil)
+
+func NewTurnIndicator(client Client, pos ui.Position) *TurnIndicator {
+	return &TurnIndicator{
+		client: client,
+		text: ui.NewText(ui.TextConfig{
+			Pos: pos,
+		}),
+	}
+}
+
+func (t *TurnIndicator) HandleTurnChangedPacket(data []byte) error {
+	var turnChanged protocol.TurnChangedPacket
+	err := json.Unmarshal(data, &turnChanged)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal packet: %w", err)
+	}
+
+	t.SetTurn(turnChanged)
+	return nil
+}
+
+// SetTurn übernimmt den Zustand, zum Beispiel aus dem Snapshot eines Spieles.
+func (t *TurnIndicator) SetTurn(turn protocol.TurnChangedPacket) {
+	t.turn = turn
+	t.hasTurn = true
+	t.receivedAt = time.Now()
+}
+
+// Current gibt den Spieler zurück, der an der Reihe ist. ok ist false, solange noch niemand an der Reihe war.
+func (t *TurnIndicator) Current() (player int32, ok bool) {
+	return t.turn.Player, t.hasTurn
+}
+
+func (t *TurnIndicator) IsMyTurn() bool {
+	return t.hasTurn && t.turn.Player == t.client.Id()
+}
+
+func millisecondsLeft(milliseconds int32, since time.Time) time.Duration {
+	left := time.Duration(milliseconds)*time.Millisecond - time.Since(since)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// TurnTimeLeft gibt zurück, wie lange der Spieler, der an der Reihe ist, noch für seinen Zug Zeit hat.
+// ok ist false, wenn die Zeit nicht begrenzt ist.
+func (t *TurnIndicator) TurnTimeLeft() (left time.Duration, ok bool) {
+	if !t.hasTurn || t.turn.TurnTimeLeft < 0 {
+		return 0, false
+	}
+	return millisecondsLeft(t.turn.TurnTimeLeft, t.receivedAt), true
+}
+
+// TotalTimeLeft gibt die verbleibende Bedenkzeit des Spielers zurück. ok ist false, wenn sie nicht begrenzt ist.
+func (t *TurnIndicator) TotalTimeLeft(player int32) (left time.Duration, ok bool) {
+	milliseconds, ok := t.turn.TotalTimeLeft[player]
+	if !ok {
+		return 0, false
+	}
+
+	if player != t.turn.Player {
+		return time.Duration(milliseconds) * time.Millisecond, true
+	}
+	return millisecondsLeft(milliseconds, t.receivedAt), true
+}
+
+// TimeLeft gibt zurück, wie lange es dauert, bis die Zeit des Spielers, der an der Reihe ist, abläuft.
+// Dabei wird sowohl die Zeit für den Zug als auch die gesamte Bedenkzeit berücksichtigt.
+// ok ist false, wenn die Zeit nicht begrenzt ist.
+func (t *TurnIndicator) TimeLeft() (left time.Duration, ok bool) {
+	turnTimeLeft, turnTimeLimited := t.TurnTimeLeft()
+	totalTimeLeft, totalTimeLimited := t.TotalTimeLeft(t.turn.Player)
+
+	switch {
+	case turnTimeLimited && totalTimeLimited:
+		if turnTimeLeft < totalTimeLeft {
+			return turnTimeLeft, true
+		}
+		return totalTimeLeft, true
+	case turnTimeLimited:
+		return turnTimeLeft, true
+	case totalTimeLimited:
+		return totalTimeLeft, true
+	default:
+		return 0, false
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (t *TurnIndicator) Update() {
+	if !t.hasTurn {
+		t.text.Text = ""
+		return
+	}
+
+	if t.IsMyTurn() {
+		t.text.Text = "Du bist am Zug"
+	} else if player, ok := t.client.GamePlayers()[t.turn.Player]; ok {
+		t.text.Text = player.Name + " ist am Zug"
+	} else {
+		t.text.Text = ""
+		return
+	}
+
+	if left, ok := t.TimeLeft(); ok {
+		t.text.Text += " (" + formatDuration(left) + ")"
+	}
+}
+
+func (t *TurnIndicator) Draw(screen *ebiten.Image) {
+	t.text.Draw(screen)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/colornames"
+)
+
+func sendStartGame(client *client, gameType availableGameType, options protocol.OptionValues) {
+	startGame, err := json.Marshal(protocol.StartGamePacket{
+		PacketName: protocol.StartGamePacketName,
+		GameType:   gameType.Name,
+		Options:    options,
+	})
+	if err != nil {
+		panic(err)
+	}
+	client.SendPacket(startGame)
+}
+
+type gameOptionRow struct {
+	option         protocol.Option
+	label          *ui.Text
+	value          *ui.Text
+	previousButton *ui.Button
+	nextButton     *ui.Button
+}
+
+// gameOptionsScreen zeigt ein Formular mit den Einstellungen eines Spieles an, das anhand der vom Server
+// mitgeteilten Einstellungen erstellt wird.
+type gameOptionsScreen struct {
+	client      *client
+	gameType    availableGameType
+	values      protocol.OptionValues
+	title       *ui.Text
+	rows        []gameOptionRow
+	startButton *ui.Button
+	problem     *ui.Text // Warum das Spiel mit den Einstellungen nicht gestartet werden kann
+}
+
+var _ screen = (*gameOptionsScreen)(nil)
+
+func newGameOptionsScreen(client *client, gameType availableGameType) *gameOptionsScreen {
+	options := gameType.data.Options
+
+	screen := &gameOptionsScreen{
+		client:   client,
+		gameType: gameType,
+		values:   options.Defaults(),
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: 100}
+			}),
+			Text:   gameType.DisplayName,
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+	}
+
+	screen.rows = make([]gameOptionRow, len(options))
+	for i, option := range options {
+		iCopy := i
+		optionCopy := option
+
+		screen.rows[i] = gameOptionRow{
+			option: option,
+			label: ui.NewText(ui.TextConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 - 250, Y: 220 + 80*iCopy}
+				}),
+				Text: option.DisplayName,
+			}),
+			value: ui.NewText(ui.TextConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 + 150, Y: 220 + 80*iCopy}
+				}),
+				Text: option.Format(option.Default),
+			}),
+			previousButton: ui.NewButton(ui.ButtonConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 + 20, Y: 220 + 80*iCopy}
+				}),
+				Text: "<",
+				Callback: func() {
+					screen.values[optionCopy.Name] = optionCopy.Previous(screen.values[optionCopy.Name])
+				},
+			}),
+			nextButton: ui.NewButton(ui.ButtonConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 + 280, Y: 220 + 80*iCopy}
+				}),
+				Text: ">",
+				Callback: func() {
+					screen.values[optionCopy.Name] = optionCopy.Next(screen.values[optionCopy.Name])
+				},
+			}),
+		}
+	}
+
+	screen.startButton = ui.NewButton(ui.ButtonConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: height - 100}
+		}),
+		Text: "Spiel starten",
+		Callback: func() {
+			if screen.checkOptions() != "" {
+				return
+			}
+			sendStartGame(client, gameType, screen.values)
+		},
+	})
+	screen.problem = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: height - 40}
+		}),
+		Colors: &ui.TextColorPalette{Color: colornames.Darkred},
+	})
+
+	return screen
+}
+
+// checkOptions gibt eine Meldung zurück, wenn die gewählten Einstellungen nicht zusammenpassen.
+func (g *gameOptionsScreen) checkOptions() string {
+	if g.gameType.CheckOptions == nil {
+		return ""
+	}
+	return g.gameType.CheckOptions(g.values)
+}
+
+func (g *gameOptionsScreen) components() []ui.Component {
+	components := []ui.Component{g.title, g.startButton, g.problem}
+
+	for _, row := range g.rows {
+		components = append(components, row.label, row.value, row.previousButton, row.nextButton)
+	}
+
+	return components
+}
+
+func (g *gameOptionsScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.client.currentScreen = newStartGameScreen(g.client)
+		return
+	}
+
+	for _, row := range g.rows {
+		row.value.Text = row.option.Format(g.values[row.option.Name])
+	}
+
+	g.problem.Text = g.checkOptions()
+	if g.problem.Text != "" {
+		g.startButton.SetColors(&ui.DisabledButtonColors)
+	} else {
+		g.startButton.SetColors(&ui.ButtonColors)
+	}
+
+	for _, component := range g.components() {
+		component.Update()
+	}
+}
+
+func (g *gameOptionsScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range g.components() {
+		component.Draw(screen)
+	}
+}
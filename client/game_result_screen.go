@@ -0,0 +1,55 @@
+package client
+
+import (
+	"github.com/Lama06/Oinky-Party/client/game"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/colornames"
+)
+
+// gameResultScreen zeigt ein beendetes game.ResultGame weiter an, bis der Spieler zur Party zurückkehrt.
+type gameResultScreen struct {
+	client   *client
+	game     game.ResultGame
+	hintText *ui.Text
+}
+
+var _ layoutScreen = (*gameResultScreen)(nil)
+
+func newGameResultScreen(client *client, game game.ResultGame) *gameResultScreen {
+	return &gameResultScreen{
+		client: client,
+		game:   game,
+		hintText: ui.NewText(ui.TextConfig{
+			Text: "Enter: Zurück zur Party",
+			Colors: &ui.TextColorPalette{
+				Color: colornames.White,
+			},
+		}),
+	}
+}
+
+func (g *gameResultScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.client.currentScreen = newPartyScreen(g.client)
+		return
+	}
+
+	g.game.Update()
+}
+
+func (g *gameResultScreen) draw(screen *ebiten.Image) {
+	g.game.Draw(screen)
+
+	width, height := screen.Size()
+	ebitenutil.DrawRect(screen, 0, float64(height-30), float64(width), 30, overlayColor)
+	g.hintText.Pos = ui.CenteredPosition{X: width / 2, Y: height - 15}
+	g.hintText.Draw(screen)
+}
+
+func (g *gameResultScreen) layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return g.game.Layout(outsideWidth, outsideHeight)
+}
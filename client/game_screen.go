@@ -4,14 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image/color"
 
+	"github.com/Lama06/Oinky-Party/client/ui"
 	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/colornames"
 )
 
+var overlayColor = color.RGBA{A: 160}
+
 type gameScreen struct {
-	client *client
+	client      *client
+	confirmEnd  bool // Ob gerade gefragt wird, ob das Spiel wirklich beendet werden soll
+	overlayText *ui.Text
 }
 
 var _ packetHandlerScreen = (*gameScreen)(nil)
@@ -19,7 +27,33 @@ var _ packetHandlerScreen = (*gameScreen)(nil)
 func newGameScreen(client *client) *gameScreen {
 	return &gameScreen{
 		client: client,
+		overlayText: ui.NewText(ui.TextConfig{
+			Colors: &ui.TextColorPalette{
+				Color: colornames.White,
+			},
+		}),
+	}
+}
+
+func (g *gameScreen) sendPauseGame(paused bool) {
+	pauseGame, err := json.Marshal(protocol.PauseGamePacket{
+		PacketName: protocol.PauseGamePacketName,
+		Paused:     paused,
+	})
+	if err != nil {
+		panic(err)
 	}
+	g.client.SendPacket(pauseGame)
+}
+
+func (g *gameScreen) sendEndGame() {
+	endGame, err := json.Marshal(protocol.EndGamePacket{
+		PacketName: protocol.EndGamePacketName,
+	})
+	if err != nil {
+		panic(err)
+	}
+	g.client.SendPacket(endGame)
 }
 
 func (g *gameScreen) update() {
@@ -27,16 +61,52 @@ func (g *gameScreen) update() {
 		return
 	}
 
-	g.client.currentGame.Update()
+	if g.confirmEnd {
+		if inpututil.IsKeyJustReleased(ebiten.KeyEnter) {
+			g.confirmEnd = false
+			g.sendEndGame()
+		} else if inpututil.IsKeyJustReleased(ebiten.KeyEscape) {
+			g.confirmEnd = false
+		}
+		return
+	}
 
 	if inpututil.IsKeyJustReleased(ebiten.KeyEscape) {
-		endGame, err := json.Marshal(protocol.EndGamePacket{
-			PacketName: protocol.EndGamePacketName,
-		})
-		if err != nil {
-			panic(err)
-		}
-		g.client.SendPacket(endGame)
+		g.confirmEnd = true
+		return
+	}
+
+	if inpututil.IsKeyJustReleased(ebiten.KeyP) {
+		g.sendPauseGame(!g.client.pause.Paused)
+	}
+
+	if !g.client.pause.Paused {
+		g.client.currentGame.Update()
+	}
+}
+
+func (g *gameScreen) pausedByText() string {
+	if g.client.pause.PausedBy == 0 {
+		return "Das Spiel wurde pausiert"
+	}
+	if player, ok := g.client.partyPlayers[g.client.pause.PausedBy]; ok {
+		return player.Name + " hat das Spiel pausiert"
+	}
+	return "Das Spiel wurde pausiert"
+}
+
+func (g *gameScreen) overlay() string {
+	switch {
+	case g.confirmEnd:
+		return "Spiel wirklich beenden? Enter: Ja, Escape: Nein"
+	case g.client.pause.Paused:
+		return fmt.Sprintf("%s - P: Fortsetzen (%d/%d)", g.pausedByText(), len(g.client.pause.Votes),
+			g.client.pause.VotesNeeded)
+	case len(g.client.pause.Votes) != 0:
+		return fmt.Sprintf("Pause beantragt (%d/%d) - P: Zustimmen", len(g.client.pause.Votes),
+			g.client.pause.VotesNeeded)
+	default:
+		return ""
 	}
 }
 
@@ -46,6 +116,19 @@ func (g *gameScreen) draw(screen *ebiten.Image) {
 	}
 
 	g.client.currentGame.Draw(screen)
+
+	text := g.overlay()
+	if text == "" {
+		return
+	}
+
+	width, height := screen.Size()
+	if g.confirmEnd || g.client.pause.Paused {
+		ebitenutil.DrawRect(screen, 0, 0, float64(width), float64(height), overlayColor)
+	}
+	g.overlayText.Pos = ui.CenteredPosition{X: width / 2, Y: height / 2}
+	g.overlayText.Text = text
+	g.overlayText.Draw(screen)
 }
 
 func (g *gameScreen) handlePacket(packet []byte) error {
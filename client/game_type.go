@@ -1,10 +1,13 @@
 package client
 
 import (
+	"fmt"
+
 	"github.com/Lama06/Oinky-Party/client/connect4"
 	"github.com/Lama06/Oinky-Party/client/flappyoinky"
 	"github.com/Lama06/Oinky-Party/client/game"
 	"github.com/Lama06/Oinky-Party/client/schiffe_versenken"
+	"github.com/Lama06/Oinky-Party/protocol"
 )
 
 var gameTypes = []game.Type{
@@ -22,3 +25,61 @@ func gameTypeByName(name string) (game.Type, bool) {
 
 	return game.Type{}, false
 }
+
+// availableGameType ist ein Spiel, das sowohl der Server anbietet als auch der Client darstellen kann.
+type availableGameType struct {
+	game.Type
+	data protocol.GameTypeData
+}
+
+func (a availableGameType) playerCountText() string {
+	switch {
+	case a.data.MaxPlayers == 0:
+		return fmt.Sprintf("ab %d Spieler", a.data.MinPlayers)
+	case a.data.MinPlayers == a.data.MaxPlayers:
+		return fmt.Sprintf("%d Spieler", a.data.MinPlayers)
+	default:
+		return fmt.Sprintf("%d-%d Spieler", a.data.MinPlayers, a.data.MaxPlayers)
+	}
+}
+
+func (a availableGameType) supportsPlayerCount(players int) bool {
+	return players >= int(a.data.MinPlayers) && (a.data.MaxPlayers == 0 || players <= int(a.data.MaxPlayers))
+}
+
+// supportsParty gibt an, ob das Spiel mit allen Spielern der Party gespielt werden kann.
+func (a availableGameType) supportsParty(players map[int32]game.PartyPlayer) bool {
+	if len(players) == 1 && a.data.SoloOpponent {
+		return true // Der Server fügt einen Bot als Gegner hinzu
+	}
+
+	if !a.supportsPlayerCount(len(players)) {
+		return false
+	}
+
+	if !a.data.SupportsBots {
+		for _, player := range players {
+			if player.Bot {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (c *client) availableGameTypes() []availableGameType {
+	var result []availableGameType
+	for _, data := range c.availableGames {
+		gameType, ok := gameTypeByName(data.Name)
+		if !ok || gameType.Version != data.Version {
+			continue
+		}
+
+		result = append(result, availableGameType{
+			Type: gameType,
+			data: data,
+		})
+	}
+	return result
+}
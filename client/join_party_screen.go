@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/Lama06/Oinky-Party/client/rescources"
 	"github.com/Lama06/Oinky-Party/client/ui"
@@ -19,13 +20,13 @@ type joinPartyScreenLoading struct {
 var _ packetHandlerScreen = (*joinPartyScreenLoading)(nil)
 
 func newJoinPartyScreenLoading(client *client) *joinPartyScreenLoading {
-	queryParties, err := json.Marshal(protocol.QueryPartiesPacket{
-		PacketName: protocol.QueryPartiesPacketName,
+	subscribeParties, err := json.Marshal(protocol.SubscribePartiesPacket{
+		PacketName: protocol.SubscribePartiesPacketName,
 	})
 	if err != nil {
 		panic(err)
 	}
-	client.SendPacket(queryParties)
+	client.SendPacket(subscribeParties)
 
 	return &joinPartyScreenLoading{
 		client: client,
@@ -38,9 +39,21 @@ func newJoinPartyScreenLoading(client *client) *joinPartyScreenLoading {
 	}
 }
 
+func leavePartyBrowser(client *client) {
+	unsubscribeParties, err := json.Marshal(protocol.UnsubscribePartiesPacket{
+		PacketName: protocol.UnsubscribePartiesPacketName,
+	})
+	if err != nil {
+		panic(err)
+	}
+	client.SendPacket(unsubscribeParties)
+
+	client.currentScreen = newTitleScreen(client)
+}
+
 func (j *joinPartyScreenLoading) update() {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		j.client.currentScreen = newTitleScreen(j.client)
+		leavePartyBrowser(j.client)
 	}
 
 	j.loadingText.Update()
@@ -96,7 +109,7 @@ func newJoinPartyScreenFailed(client *client) *joinPartyScreenFailed {
 
 func (j *joinPartyScreenFailed) update() {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		j.client.currentScreen = newTitleScreen(j.client)
+		leavePartyBrowser(j.client)
 	}
 
 	j.failedText.Update()
@@ -110,23 +123,88 @@ func (j *joinPartyScreenFailed) draw(screen *ebiten.Image) {
 type joinPartyScreenSuccess struct {
 	client  *client
 	title   *ui.Text
+	parties map[int32]protocol.PartyData
 	buttons []*ui.Button
 }
 
-var _ screen = (*joinPartyScreenSuccess)(nil)
+var _ packetHandlerScreen = (*joinPartyScreenSuccess)(nil)
 
 func newJoinPartyScreenSuccess(client *client, packet protocol.ListPartiesPacket) *joinPartyScreenSuccess {
-	buttons := make([]*ui.Button, len(packet.Parties))
-	for i, party := range packet.Parties {
+	parties := make(map[int32]protocol.PartyData, len(packet.Parties))
+	for _, party := range packet.Parties {
+		parties[party.Id] = party
+	}
+
+	screen := &joinPartyScreenSuccess{
+		client: client,
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 3}
+			}),
+			Text:   "Party beitreten",
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+		parties: parties,
+	}
+	screen.createButtons()
+	return screen
+}
+
+func (j *joinPartyScreenSuccess) partiesSorted() []protocol.PartyData {
+	parties := make([]protocol.PartyData, 0, len(j.parties))
+	for _, party := range j.parties {
+		parties = append(parties, party)
+	}
+	sort.Slice(parties, func(i, k int) bool {
+		if parties[i].Name != parties[k].Name {
+			return parties[i].Name < parties[k].Name
+		}
+		return parties[i].Id < parties[k].Id
+	})
+	return parties
+}
+
+func partyButtonText(party protocol.PartyData) string {
+	text := fmt.Sprintf("%s (%d/%d Spieler)", party.Name, len(party.Players), protocol.MaxPartySize)
+
+	if party.GameRunning {
+		displayName := party.GameType
+		if gameType, ok := gameTypeByName(party.GameType); ok {
+			displayName = gameType.DisplayName
+		}
+		text += " - spielt " + displayName
+	} else if party.Full() {
+		text += " - voll"
+	}
+
+	return text
+}
+
+func (j *joinPartyScreenSuccess) createButtons() {
+	parties := j.partiesSorted()
+
+	j.buttons = make([]*ui.Button, len(parties))
+	for i, party := range parties {
 		iCopy := i
 		partyCopy := party
 
-		buttons[i] = ui.NewButton(ui.ButtonConfig{
+		colors := &ui.ButtonColors
+		if !party.Joinable() {
+			colors = &ui.DisabledButtonColors
+		}
+
+		j.buttons[i] = ui.NewButton(ui.ButtonConfig{
 			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
 				return ui.CenteredPosition{X: width / 2, Y: height/3*2 + 100*iCopy}
 			}),
-			Text: fmt.Sprintf("%s (%d Spieler)", party.Name, len(party.Players)),
+			Text:   partyButtonText(party),
+			Colors: colors,
 			Callback: func() {
+				if !partyCopy.Joinable() {
+					return
+				}
+
 				joinParty, err := json.Marshal(protocol.JoinPartyPacket{
 					PacketName: protocol.JoinPartyPacketName,
 					Id:         partyCopy.Id,
@@ -134,28 +212,15 @@ func newJoinPartyScreenSuccess(client *client, packet protocol.ListPartiesPacket
 				if err != nil {
 					panic(err)
 				}
-				client.SendPacket(joinParty)
+				j.client.SendPacket(joinParty)
 			},
 		})
 	}
-
-	return &joinPartyScreenSuccess{
-		client: client,
-		title: ui.NewText(ui.TextConfig{
-			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
-				return ui.CenteredPosition{X: width / 2, Y: height / 3}
-			}),
-			Text:   "Party beitreten",
-			Colors: &ui.TitleColors,
-			Font:   rescources.RobotoTitleFont,
-		}),
-		buttons: buttons,
-	}
 }
 
 func (j *joinPartyScreenSuccess) update() {
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		j.client.currentScreen = newTitleScreen(j.client)
+		leavePartyBrowser(j.client)
 	}
 
 	j.title.Update()
@@ -172,3 +237,53 @@ func (j *joinPartyScreenSuccess) draw(screen *ebiten.Image) {
 		button.Draw(screen)
 	}
 }
+
+func (j *joinPartyScreenSuccess) handlePacket(data []byte) error {
+	packetName, err := protocol.GetPacketName(data)
+	if err != nil {
+		return fmt.Errorf("failed to get packet name: %w", err)
+	}
+
+	switch packetName {
+	case protocol.ListPartiesPacketName:
+		var listParties protocol.ListPartiesPacket
+		err := json.Unmarshal(data, &listParties)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		j.parties = make(map[int32]protocol.PartyData, len(listParties.Parties))
+		for _, party := range listParties.Parties {
+			j.parties[party.Id] = party
+		}
+	case protocol.PartyCreatedPacketName:
+		var partyCreated protocol.PartyCreatedPacket
+		err := json.Unmarshal(data, &partyCreated)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		j.parties[partyCreated.Party.Id] = partyCreated.Party
+	case protocol.PartyUpdatedPacketName:
+		var partyUpdated protocol.PartyUpdatedPacket
+		err := json.Unmarshal(data, &partyUpdated)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		j.parties[partyUpdated.Party.Id] = partyUpdated.Party
+	case protocol.PartyRemovedPacketName:
+		var partyRemoved protocol.PartyRemovedPacket
+		err := json.Unmarshal(data, &partyRemoved)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		delete(j.parties, partyRemoved.Id)
+	default:
+		return fmt.Errorf("unknown packet name: %s", packetName)
+	}
+
+	j.createButtons()
+	return nil
+}
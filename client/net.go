@@ -5,28 +5,46 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"sync"
 
 	"github.com/Lama06/Oinky-Party/protocol"
 )
 
-func (c *client) connect(address string) error {
+// connection ist eine Verbindung zum Server. Nach einem Verbindungsabbruch wird eine neue Verbindung aufgebaut.
+type connection struct {
+	conn           net.Conn
+	send           chan []byte
+	receive        chan []byte
+	disconnected   chan struct{}
+	closed         chan struct{} // Wird beim Verbindungsabbruch geschlossen, damit die Goroutinen der Verbindung enden
+	disconnectOnce sync.Once
+}
+
+func connect(address string) (*connection, error) {
 	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", address, protocol.Port))
 	if err != nil {
-		return fmt.Errorf("failed dial the server: %w", err)
+		return nil, fmt.Errorf("failed dial the server: %w", err)
 	}
 	err = conn.(*net.TCPConn).SetKeepAlive(true)
 	if err != nil {
-		return fmt.Errorf("failed to change the keep alive state: %w", err)
+		return nil, fmt.Errorf("failed to change the keep alive state: %w", err)
+	}
+
+	c := &connection{
+		conn:         conn,
+		send:         make(chan []byte, 100),
+		receive:      make(chan []byte, 100),
+		disconnected: make(chan struct{}, 1),
+		closed:       make(chan struct{}),
 	}
-	c.conn = conn
 
 	go c.forwardMessagesToServer()
 	go c.forwardMessagesFromServer()
 
-	return nil
+	return c, nil
 }
 
-func (c *client) forwardMessagesFromServer() {
+func (c *connection) forwardMessagesFromServer() {
 	defer c.disconnect()
 
 	for {
@@ -47,14 +65,25 @@ func (c *client) forwardMessagesFromServer() {
 			msgIn = append(msgIn, msgInBuffer[:n]...)
 		}
 
-		c.receive <- msgIn
+		select {
+		case c.receive <- msgIn:
+		case <-c.closed:
+			return
+		}
 	}
 }
 
-func (c *client) forwardMessagesToServer() {
+func (c *connection) forwardMessagesToServer() {
 	defer c.disconnect()
 
-	for msgOut := range c.send {
+	for {
+		var msgOut []byte
+		select {
+		case msgOut = <-c.send:
+		case <-c.closed:
+			return
+		}
+
 		msgOutSize := protocol.Int32ToBytes(int32(len(msgOut)))
 
 		_, err := c.conn.Write([]byte{msgOutSize[0], msgOutSize[1], msgOutSize[2], msgOutSize[3]})
@@ -69,7 +98,7 @@ func (c *client) forwardMessagesToServer() {
 	}
 }
 
-func (c *client) SendPacket(packet []byte) {
+func (c *connection) sendPacket(packet []byte) {
 	select {
 	case c.send <- packet:
 		return
@@ -79,12 +108,17 @@ func (c *client) SendPacket(packet []byte) {
 	}
 }
 
-func (c *client) disconnect() {
+func (c *connection) disconnect() {
 	c.disconnectOnce.Do(func() {
 		err := c.conn.Close()
 		if err != nil {
 			log.Println(fmt.Errorf("error while closing connection to server: %w", err))
 		}
+		close(c.closed)
 		c.disconnected <- struct{}{}
 	})
 }
+
+func (c *client) SendPacket(packet []byte) {
+	c.connection.sendPacket(packet)
+}
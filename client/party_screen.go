@@ -2,6 +2,8 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/Lama06/Oinky-Party/client/rescources"
 	"github.com/Lama06/Oinky-Party/client/ui"
@@ -11,21 +13,31 @@ import (
 )
 
 type partyScreenPlayerName struct {
-	id   int32
-	text *ui.Text
+	id           int32
+	isHost       bool
+	team         int32
+	text         *ui.Text
+	teamButton   *ui.Button // Nur der Host kann die Teams der Spieler ändern
+	removeButton *ui.Button // Nur der Host kann Bots entfernen
 }
 
 type partyScreen struct {
-	client          *client
-	title           *ui.Text
-	playersNames    []partyScreenPlayerName
-	startGameButton *ui.Button
+	client             *client
+	title              *ui.Text
+	lastResult         *ui.Text
+	clientIsHost       bool // Ob der Client Host war, als die Spielerliste erstellt wurde
+	playersNames       []partyScreenPlayerName
+	balanceTeamsButton *ui.Button
+	addBotButton       *ui.Button
+	startGameButton    *ui.Button
+	playlistButton     *ui.Button
+	rematchButton      *ui.Button
 }
 
 var _ screen = (*partyScreen)(nil)
 
 func newPartyScreen(client *client) *partyScreen {
-	return &partyScreen{
+	screen := &partyScreen{
 		client: client,
 		title: ui.NewText(ui.TextConfig{
 			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
@@ -44,46 +56,253 @@ func newPartyScreen(client *client) *partyScreen {
 				client.currentScreen = newStartGameScreen(client)
 			},
 		}),
+		playlistButton: ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height - 200}
+			}),
+			Text: "Playlist",
+			Callback: func() {
+				client.currentScreen = newPlaylistScreen(client)
+			},
+		}),
+		rematchButton: ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height/3 - 160}
+			}),
+			Callback: func() {
+				if !client.canVoteForRematch() {
+					return
+				}
+
+				rematch, err := json.Marshal(protocol.RematchPacket{
+					PacketName: protocol.RematchPacketName,
+				})
+				if err != nil {
+					panic(err)
+				}
+				client.SendPacket(rematch)
+			},
+		}),
+	}
+
+	if client.lastResult != nil {
+		screen.lastResult = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height/3 - 80}
+			}),
+			Text: resultText(client, *client.lastResult),
+		})
+	}
+
+	return screen
+}
+
+func teamName(team int32) string {
+	if team == protocol.NoTeam {
+		return "Kein Team"
+	}
+	return fmt.Sprintf("Team %d", team)
+}
+
+// resultText beschreibt die Gewinner eines Spieles.
+func resultText(client *client, result protocol.GameResultData) string {
+	if len(result.TeamRanking) != 0 {
+		winners := make([]string, len(result.TeamRanking[0]))
+		for i, team := range result.TeamRanking[0] {
+			winners[i] = teamName(team)
+		}
+		return "Gewonnen: " + strings.Join(winners, ", ")
+	}
+
+	if len(result.Ranking) == 0 {
+		return ""
+	}
+
+	winners := make([]string, 0, len(result.Ranking[0]))
+	for _, id := range result.Ranking[0] {
+		if player, ok := client.partyPlayers[id]; ok {
+			winners = append(winners, player.Name)
+		}
+	}
+	return "Gewonnen: " + strings.Join(winners, ", ")
+}
+
+func (p *partyScreen) updateRematchButton() {
+	text := fmt.Sprintf("Nochmal spielen (%d/%d)", len(p.client.rematch.Votes), len(p.client.rematch.Players))
+	if text != p.rematchButton.Text() {
+		p.rematchButton.SetText(text)
+	}
+
+	colors := &ui.ButtonColors
+	if !p.client.canVoteForRematch() {
+		colors = &ui.DisabledButtonColors
+	}
+	if colors != p.rematchButton.Colors() {
+		p.rematchButton.SetColors(colors)
 	}
 }
 
 func (p *partyScreen) arePlayerNamesValid() bool {
+	if p.clientIsHost != p.client.isHost() {
+		return false
+	}
+
 	players := p.client.partyPlayersSorted()
 	if len(players) != len(p.playersNames) {
 		return false
 	}
 	for i, player := range players {
-		if p.playersNames[i].id != player.Id {
+		playerName := p.playersNames[i]
+		if playerName.id != player.Id || playerName.isHost != (player.Id == p.client.partyHost) || playerName.team != player.Team {
 			return false
 		}
 	}
 	return true
 }
 
+func (p *partyScreen) sendSetTeam(player int32, team int32) {
+	setTeam, err := json.Marshal(protocol.SetTeamPacket{
+		PacketName: protocol.SetTeamPacketName,
+		Player:     player,
+		Team:       team,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.client.SendPacket(setTeam)
+}
+
 func (p *partyScreen) updatePlayerList() {
 	players := p.client.partyPlayersSorted()
+	p.clientIsHost = p.client.isHost()
 
 	p.playersNames = make([]partyScreenPlayerName, len(players))
 	for i, player := range players {
 		iCopy := i
+		playerCopy := player
+		isHost := player.Id == p.client.partyHost
+
+		text := player.Name
+		if isHost {
+			text += " (Host)"
+		}
+		if player.Bot {
+			text += " (Bot)"
+		}
+		if !p.clientIsHost && player.Team != protocol.NoTeam {
+			text += " - " + teamName(player.Team)
+		}
+
+		var teamButton *ui.Button
+		if p.clientIsHost {
+			teamButton = ui.NewButton(ui.ButtonConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 + 250, Y: 100 + height/3 + 100*iCopy}
+				}),
+				Text: teamName(player.Team),
+				Callback: func() {
+					p.sendSetTeam(playerCopy.Id, (playerCopy.Team+1)%(protocol.MaxTeams+1))
+				},
+			})
+		}
+
+		var removeButton *ui.Button
+		if p.clientIsHost && player.Bot {
+			removeButton = ui.NewButton(ui.ButtonConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/2 + 420, Y: 100 + height/3 + 100*iCopy}
+				}),
+				Text: "X",
+				Callback: func() {
+					removeBot, err := json.Marshal(protocol.RemoveBotPacket{
+						PacketName: protocol.RemoveBotPacketName,
+						Id:         playerCopy.Id,
+					})
+					if err != nil {
+						panic(err)
+					}
+					p.client.SendPacket(removeBot)
+				},
+			})
+		}
+
 		p.playersNames[i] = partyScreenPlayerName{
-			id: player.Id,
+			id:     player.Id,
+			isHost: isHost,
+			team:   player.Team,
 			text: ui.NewText(ui.TextConfig{
 				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
 					return ui.CenteredPosition{X: width / 2, Y: 100 + height/3 + 100*iCopy}
 				}),
-				Text: player.Name,
+				Text: text,
 			}),
+			teamButton:   teamButton,
+			removeButton: removeButton,
 		}
 	}
+
+	p.balanceTeamsButton = nil
+	p.addBotButton = nil
+	if p.clientIsHost {
+		p.balanceTeamsButton = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width/2 - 200, Y: height - 300}
+			}),
+			Text: "2 Teams bilden",
+			Callback: func() {
+				balanceTeams, err := json.Marshal(protocol.BalanceTeamsPacket{
+					PacketName: protocol.BalanceTeamsPacketName,
+					Teams:      2,
+				})
+				if err != nil {
+					panic(err)
+				}
+				p.client.SendPacket(balanceTeams)
+			},
+		})
+
+		p.addBotButton = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width/2 + 200, Y: height - 300}
+			}),
+			Text: "Bot hinzufügen",
+			Callback: func() {
+				addBot, err := json.Marshal(protocol.AddBotPacket{
+					PacketName: protocol.AddBotPacketName,
+				})
+				if err != nil {
+					panic(err)
+				}
+				p.client.SendPacket(addBot)
+			},
+		})
+	}
 }
 
 func (p *partyScreen) components() []ui.Component {
 	components := make([]ui.Component, 0)
-	components = append(components, p.title, p.startGameButton)
+	components = append(components, p.title, p.startGameButton, p.playlistButton)
+
+	if p.lastResult != nil {
+		components = append(components, p.lastResult)
+	}
+
+	if p.client.rematch.Available {
+		components = append(components, p.rematchButton)
+	}
+
+	if p.balanceTeamsButton != nil {
+		components = append(components, p.balanceTeamsButton, p.addBotButton)
+	}
 
 	for _, playerName := range p.playersNames {
 		components = append(components, playerName.text)
+		if playerName.teamButton != nil {
+			components = append(components, playerName.teamButton)
+		}
+		if playerName.removeButton != nil {
+			components = append(components, playerName.removeButton)
+		}
 	}
 
 	return components
@@ -104,6 +323,10 @@ func (p *partyScreen) update() {
 		p.updatePlayerList()
 	}
 
+	if p.client.rematch.Available {
+		p.updateRematchButton()
+	}
+
 	for _, component := range p.components() {
 		component.Update()
 	}
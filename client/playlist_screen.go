@@ -0,0 +1,251 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const maxPlaylistRounds = 10
+
+type playlistScreen struct {
+	client          *client
+	playlistVersion int  // Die Version der Playlist, für die die Komponenten erstellt wurden
+	isHost          bool // Ob der Client Host war, als die Komponenten erstellt wurden
+	rounds          int32
+	title           *ui.Text
+	entries         []*ui.Text
+	removeButtons   []*ui.Button
+	roundsText      *ui.Text
+	lessRounds      *ui.Button
+	moreRounds      *ui.Button
+	addButtons      []*ui.Button
+	startStopButton *ui.Button
+	notHostText     *ui.Text
+}
+
+var _ screen = (*playlistScreen)(nil)
+
+func newPlaylistScreen(client *client) *playlistScreen {
+	screen := &playlistScreen{
+		client: client,
+		rounds: 1,
+	}
+	screen.createComponents()
+	return screen
+}
+
+func (p *playlistScreen) sendPacket(packet any) {
+	data, err := json.Marshal(packet)
+	if err != nil {
+		panic(err)
+	}
+	p.client.SendPacket(data)
+}
+
+func (p *playlistScreen) createComponents() {
+	p.playlistVersion = p.client.playlistVersion
+	p.isHost = p.client.isHost()
+	playlist := p.client.playlist
+
+	title := "Playlist"
+	if playlist.Running {
+		title = "Playlist (läuft)"
+	}
+	p.title = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: 80}
+		}),
+		Text:   title,
+		Colors: &ui.TitleColors,
+		Font:   rescources.RobotoTitleFont,
+	})
+
+	p.entries = make([]*ui.Text, len(playlist.Entries))
+	p.removeButtons = nil
+	for i, entry := range playlist.Entries {
+		iCopy := i
+
+		displayName := entry.GameType
+		if gameType, ok := gameTypeByName(entry.GameType); ok {
+			displayName = gameType.DisplayName
+		}
+
+		p.entries[i] = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 3, Y: 200 + 80*iCopy}
+			}),
+			Text: fmt.Sprintf("%d. %s (%dx)", i+1, displayName, entry.Rounds),
+		})
+
+		if p.isHost && !playlist.Running {
+			p.removeButtons = append(p.removeButtons, ui.NewButton(ui.ButtonConfig{
+				Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+					return ui.CenteredPosition{X: width/3 + 250, Y: 200 + 80*iCopy}
+				}),
+				Text: "X",
+				Callback: func() {
+					p.sendPacket(protocol.RemovePlaylistEntryPacket{
+						PacketName: protocol.RemovePlaylistEntryPacketName,
+						Index:      int32(iCopy),
+					})
+				},
+			}))
+		}
+	}
+
+	p.roundsText = nil
+	p.lessRounds = nil
+	p.moreRounds = nil
+	p.addButtons = nil
+	p.startStopButton = nil
+	p.notHostText = nil
+
+	if !p.isHost {
+		p.notHostText = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height - 100}
+			}),
+			Text: "Nur der Host kann die Playlist bearbeiten",
+		})
+		return
+	}
+
+	if playlist.Running {
+		p.startStopButton = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height - 100}
+			}),
+			Text: "Playlist stoppen",
+			Callback: func() {
+				p.sendPacket(protocol.StopPlaylistPacket{
+					PacketName: protocol.StopPlaylistPacketName,
+				})
+			},
+		})
+		return
+	}
+
+	p.roundsText = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 3 * 2, Y: 200}
+		}),
+		Text: fmt.Sprintf("Runden: %d", p.rounds),
+	})
+	p.lessRounds = ui.NewButton(ui.ButtonConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width/3*2 - 150, Y: 200}
+		}),
+		Text: "-",
+		Callback: func() {
+			if p.rounds > 1 {
+				p.rounds--
+			}
+		},
+	})
+	p.moreRounds = ui.NewButton(ui.ButtonConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width/3*2 + 150, Y: 200}
+		}),
+		Text: "+",
+		Callback: func() {
+			if p.rounds < maxPlaylistRounds {
+				p.rounds++
+			}
+		},
+	})
+
+	gameTypes := p.client.availableGameTypes()
+	p.addButtons = make([]*ui.Button, len(gameTypes))
+	for i, gameType := range gameTypes {
+		iCopy := i
+		gameTypeCopy := gameType
+
+		p.addButtons[i] = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 3 * 2, Y: 300 + 100*iCopy}
+			}),
+			Text: "+ " + gameType.DisplayName,
+			Callback: func() {
+				p.sendPacket(protocol.AddPlaylistEntryPacket{
+					PacketName: protocol.AddPlaylistEntryPacketName,
+					GameType:   gameTypeCopy.Name,
+					Rounds:     p.rounds,
+				})
+			},
+		})
+	}
+
+	p.startStopButton = ui.NewButton(ui.ButtonConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: height - 100}
+		}),
+		Text: "Playlist starten",
+		Callback: func() {
+			p.sendPacket(protocol.StartPlaylistPacket{
+				PacketName: protocol.StartPlaylistPacketName,
+			})
+		},
+	})
+}
+
+func (p *playlistScreen) components() []ui.Component {
+	components := []ui.Component{p.title}
+
+	for _, entry := range p.entries {
+		components = append(components, entry)
+	}
+
+	for _, button := range p.removeButtons {
+		components = append(components, button)
+	}
+
+	if p.roundsText != nil {
+		components = append(components, p.roundsText, p.lessRounds, p.moreRounds)
+	}
+
+	for _, button := range p.addButtons {
+		components = append(components, button)
+	}
+
+	if p.startStopButton != nil {
+		components = append(components, p.startStopButton)
+	}
+
+	if p.notHostText != nil {
+		components = append(components, p.notHostText)
+	}
+
+	return components
+}
+
+func (p *playlistScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		p.client.currentScreen = newPartyScreen(p.client)
+		return
+	}
+
+	if p.playlistVersion != p.client.playlistVersion || p.isHost != p.client.isHost() {
+		p.createComponents()
+	}
+
+	if p.roundsText != nil {
+		p.roundsText.Text = fmt.Sprintf("Runden: %d", p.rounds)
+	}
+
+	for _, component := range p.components() {
+		component.Update()
+	}
+}
+
+func (p *playlistScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range p.components() {
+		component.Draw(screen)
+	}
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Lama06/Oinky-Party/client/game"
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type quickPlayScreen struct {
+	client      *client
+	title       *ui.Text
+	gameButtons []*ui.Button
+}
+
+var _ screen = (*quickPlayScreen)(nil)
+
+func newQuickPlayScreen(client *client) *quickPlayScreen {
+	gameTypes := client.availableGameTypes()
+
+	gameButtons := make([]*ui.Button, len(gameTypes))
+	for i, gameType := range gameTypes {
+		iCopy := i
+		gameTypeCopy := gameType
+
+		gameButtons[i] = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: 100 + height/3 + 100*iCopy}
+			}),
+			Text: gameType.DisplayName + " (" + gameType.playerCountText() + ")",
+			Callback: func() {
+				findMatch, err := json.Marshal(protocol.FindMatchPacket{
+					PacketName: protocol.FindMatchPacketName,
+					GameType:   gameTypeCopy.Name,
+				})
+				if err != nil {
+					panic(err)
+				}
+				client.SendPacket(findMatch)
+
+				client.currentScreen = newMatchmakingScreen(client, gameTypeCopy.Type)
+			},
+		})
+	}
+
+	return &quickPlayScreen{
+		client: client,
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 3}
+			}),
+			Text:   "Schnelles Spiel",
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+		gameButtons: gameButtons,
+	}
+}
+
+func (q *quickPlayScreen) components() []ui.Component {
+	components := []ui.Component{q.title}
+
+	for _, button := range q.gameButtons {
+		components = append(components, button)
+	}
+
+	return components
+}
+
+func (q *quickPlayScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		q.client.currentScreen = newTitleScreen(q.client)
+		return
+	}
+
+	for _, component := range q.components() {
+		component.Update()
+	}
+}
+
+func (q *quickPlayScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range q.components() {
+		component.Draw(screen)
+	}
+}
+
+type matchmakingScreen struct {
+	client       *client
+	title        *ui.Text
+	statusText   *ui.Text
+	cancelButton *ui.Button
+}
+
+var _ packetHandlerScreen = (*matchmakingScreen)(nil)
+
+func newMatchmakingScreen(client *client, gameType game.Type) *matchmakingScreen {
+	screen := &matchmakingScreen{
+		client: client,
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 3}
+			}),
+			Text:   "Suche " + gameType.DisplayName,
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+		statusText: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 2}
+			}),
+			Text: "Warte auf Mitspieler...",
+		}),
+	}
+
+	screen.cancelButton = ui.NewButton(ui.ButtonConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: height / 3 * 2}
+		}),
+		Text:     "Abbrechen",
+		Callback: screen.cancel,
+	})
+
+	return screen
+}
+
+func (m *matchmakingScreen) cancel() {
+	cancelFindMatch, err := json.Marshal(protocol.CancelFindMatchPacket{
+		PacketName: protocol.CancelFindMatchPacketName,
+	})
+	if err != nil {
+		panic(err)
+	}
+	m.client.SendPacket(cancelFindMatch)
+
+	m.client.currentScreen = newTitleScreen(m.client)
+}
+
+func (m *matchmakingScreen) components() []ui.Component {
+	return []ui.Component{m.title, m.statusText, m.cancelButton}
+}
+
+func (m *matchmakingScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		m.cancel()
+		return
+	}
+
+	for _, component := range m.components() {
+		component.Update()
+	}
+}
+
+func (m *matchmakingScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range m.components() {
+		component.Draw(screen)
+	}
+}
+
+func (m *matchmakingScreen) handlePacket(data []byte) error {
+	packetName, err := protocol.GetPacketName(data)
+	if err != nil {
+		return fmt.Errorf("failed to get packet name: %w", err)
+	}
+
+	switch packetName {
+	case protocol.MatchmakingStatusPacketName:
+		var status protocol.MatchmakingStatusPacket
+		err := json.Unmarshal(data, &status)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		m.statusText.Text = fmt.Sprintf("Position %d von %d in der Warteschlange", status.Position, status.QueueSize)
+
+		return nil
+	default:
+		return fmt.Errorf("unknown packet name: %s", packetName)
+	}
+}
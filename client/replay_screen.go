@@ -0,0 +1,400 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/client/game"
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	replaySeekStep = 5 * time.Second
+	replayMinSpeed = 0.25
+	replayMaxSpeed = 4
+	replaysPerPage = 5 // Damit die Liste auch bei vielen Aufzeichnungen auf den Bildschirm passt
+)
+
+func loadReplay(path string) (protocol.Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return protocol.Replay{}, fmt.Errorf("failed to read the replay file: %w", err)
+	}
+
+	var replay protocol.Replay
+	err = json.Unmarshal(data, &replay)
+	if err != nil {
+		return protocol.Replay{}, fmt.Errorf("failed to unmarshal the replay: %w", err)
+	}
+
+	return replay, nil
+}
+
+type replayListScreen struct {
+	client       *client
+	title        *ui.Text
+	noFiles      *ui.Text
+	buttons      []*ui.Button
+	page         int
+	previousPage *ui.Button
+	nextPage     *ui.Button
+}
+
+var _ screen = (*replayListScreen)(nil)
+
+func newReplayListScreen(client *client) *replayListScreen {
+	screen := &replayListScreen{
+		client: client,
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 3}
+			}),
+			Text:   "Wiederholungen",
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+	}
+
+	files, err := filepath.Glob(filepath.Join(client.replayDir, "*"+protocol.ReplayFileExtension))
+	if err != nil {
+		log.Println(fmt.Errorf("failed to list replay files: %w", err))
+	}
+	// Die neuesten Aufzeichnungen zuerst
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+
+	if len(files) == 0 {
+		screen.noFiles = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 2}
+			}),
+			Text: fmt.Sprintf("Keine Wiederholungen in %s gefunden", client.replayDir),
+		})
+	}
+
+	screen.buttons = make([]*ui.Button, len(files))
+	for i, file := range files {
+		iCopy := i
+		fileCopy := file
+
+		screen.buttons[i] = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height/3 + 150 + 100*(iCopy%replaysPerPage)}
+			}),
+			Text: strings.TrimSuffix(filepath.Base(file), protocol.ReplayFileExtension),
+			Callback: func() {
+				replay, err := loadReplay(fileCopy)
+				if err != nil {
+					log.Println(fmt.Errorf("failed to load replay: %w", err))
+					return
+				}
+
+				replayScreen, err := newReplayScreen(client, replay)
+				if err != nil {
+					log.Println(fmt.Errorf("failed to start replay: %w", err))
+					return
+				}
+				client.currentScreen = replayScreen
+			},
+		})
+	}
+
+	if len(files) > replaysPerPage {
+		screen.previousPage = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width/2 - 150, Y: height/3 + 150 + 100*replaysPerPage}
+			}),
+			Text: "Zurück",
+			Callback: func() {
+				screen.setPage(screen.page - 1)
+			},
+		})
+		screen.nextPage = ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width/2 + 150, Y: height/3 + 150 + 100*replaysPerPage}
+			}),
+			Text: "Weiter",
+			Callback: func() {
+				screen.setPage(screen.page + 1)
+			},
+		})
+		screen.setPage(0)
+	}
+
+	return screen
+}
+
+func (r *replayListScreen) pages() int {
+	return (len(r.buttons) + replaysPerPage - 1) / replaysPerPage
+}
+
+// setPage zeigt die Seite page der Liste an, wenn es sie gibt.
+func (r *replayListScreen) setPage(page int) {
+	if page < 0 || page >= r.pages() {
+		return
+	}
+	r.page = page
+	r.title.Text = fmt.Sprintf("Wiederholungen (%d/%d)", page+1, r.pages())
+
+	r.previousPage.SetColors(&ui.ButtonColors)
+	if page == 0 {
+		r.previousPage.SetColors(&ui.DisabledButtonColors)
+	}
+	r.nextPage.SetColors(&ui.ButtonColors)
+	if page == r.pages()-1 {
+		r.nextPage.SetColors(&ui.DisabledButtonColors)
+	}
+}
+
+func (r *replayListScreen) components() []ui.Component {
+	components := []ui.Component{r.title}
+	if r.noFiles != nil {
+		components = append(components, r.noFiles)
+	}
+
+	end := (r.page + 1) * replaysPerPage
+	if end > len(r.buttons) {
+		end = len(r.buttons)
+	}
+	for _, button := range r.buttons[r.page*replaysPerPage : end] {
+		components = append(components, button)
+	}
+
+	if r.previousPage != nil {
+		components = append(components, r.previousPage, r.nextPage)
+	}
+	return components
+}
+
+func (r *replayListScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		r.client.currentScreen = newTitleScreen(r.client)
+		return
+	}
+
+	for _, component := range r.components() {
+		component.Update()
+	}
+}
+
+func (r *replayListScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range r.components() {
+		component.Draw(screen)
+	}
+}
+
+// replayClient ist der game.Client, den ein Spiel bei der Wiedergabe erhält. Der Client schaut dem Spiel zu und kann
+// keine Packets senden.
+type replayClient struct {
+	replay protocol.Replay
+}
+
+var _ game.Client = replayClient{}
+
+func (r replayClient) Name() string {
+	return ""
+}
+
+func (r replayClient) Id() int32 {
+	return 0
+}
+
+func (r replayClient) PartyName() string {
+	return r.replay.PartyName
+}
+
+func (r replayClient) PartyId() int32 {
+	return 0
+}
+
+func (r replayClient) PartyPlayers() map[int32]game.PartyPlayer {
+	partyPlayers := make(map[int32]game.PartyPlayer, len(r.replay.Players))
+	for _, player := range r.replay.Players {
+		partyPlayers[player.Id] = game.PartyPlayer{
+			Name: player.Name,
+			Id:   player.Id,
+			Team: player.Team,
+			Bot:  player.Bot,
+		}
+	}
+	return partyPlayers
+}
+
+func (r replayClient) GamePlayers() map[int32]game.PartyPlayer {
+	partyPlayers := r.PartyPlayers()
+	gamePlayers := make(map[int32]game.PartyPlayer, len(r.replay.Participants))
+	for _, id := range r.replay.Participants {
+		if player, ok := partyPlayers[id]; ok {
+			gamePlayers[id] = player
+		}
+	}
+	return gamePlayers
+}
+
+func (r replayClient) Spectating() bool {
+	return true
+}
+
+func (r replayClient) GameOptions() protocol.OptionValues {
+	return r.replay.Options
+}
+
+func (r replayClient) SendPacket([]byte) {}
+
+// replayScreen spielt eine Aufzeichnung ab, indem die aufgezeichneten Packets zur ursprünglichen Zeit an das Spiel
+// übergeben werden.
+type replayScreen struct {
+	client   *client
+	replay   protocol.Replay
+	gameType game.Type
+	game     game.Game
+	next     int           // Der Index des nächsten Packets, das an das Spiel übergeben wird
+	time     time.Duration // Die aktuelle Zeit der Wiedergabe
+	speed    float64
+	paused   bool
+	finished bool // Ob dem Spiel bereits das Ergebnis der Aufzeichnung übergeben wurde
+	status   *ui.Text
+}
+
+var _ layoutScreen = (*replayScreen)(nil)
+
+func newReplayScreen(client *client, replay protocol.Replay) (*replayScreen, error) {
+	gameType, ok := gameTypeByName(replay.GameType)
+	if !ok {
+		return nil, fmt.Errorf("unknown game type: %s", replay.GameType)
+	}
+	if gameType.Version != replay.GameVersion {
+		return nil, fmt.Errorf("the replay was recorded with version %d of the game but the client has version %d",
+			replay.GameVersion, gameType.Version)
+	}
+
+	screen := &replayScreen{
+		client:   client,
+		replay:   replay,
+		gameType: gameType,
+		speed:    1,
+		status: ui.NewText(ui.TextConfig{
+			Pos: ui.TopLeftCornerPosition{X: 10, Y: 10},
+		}),
+	}
+	screen.restart()
+	return screen, nil
+}
+
+// restart erstellt das Spiel neu und beginnt die Wiedergabe von vorne.
+func (r *replayScreen) restart() {
+	r.game = r.gameType.Creator(replayClient{replay: r.replay})
+	r.game.HandleGameStarted()
+	r.next = 0
+	r.time = 0
+	r.finished = false
+}
+
+func (r *replayScreen) duration() time.Duration {
+	return time.Duration(r.replay.Duration) * time.Millisecond
+}
+
+// seek springt zur angegebenen Zeit. Beim Zurückspulen wird das Spiel neu erstellt und alle Packets bis zu dieser Zeit
+// erneut übergeben.
+func (r *replayScreen) seek(to time.Duration) {
+	if to < 0 {
+		to = 0
+	}
+	if to > r.duration() {
+		to = r.duration()
+	}
+
+	if to < r.time {
+		r.restart()
+	}
+	r.time = to
+	r.handlePackets()
+}
+
+// handlePackets übergibt alle Packets an das Spiel, deren Zeit bereits erreicht wurde.
+func (r *replayScreen) handlePackets() {
+	for r.next < len(r.replay.Packets) {
+		packet := r.replay.Packets[r.next]
+		if time.Duration(packet.Time)*time.Millisecond > r.time {
+			return
+		}
+
+		err := r.game.HandlePacket(packet.Data)
+		if err != nil {
+			log.Println(fmt.Errorf("game failed to handle replay packet: %w", err))
+		}
+		r.next++
+	}
+}
+
+func (r *replayScreen) statusText() string {
+	state := "Wiedergabe"
+	if r.paused {
+		state = "Pausiert"
+	} else if r.time >= r.duration() {
+		state = "Beendet"
+	}
+
+	return fmt.Sprintf("%s %s / %s (%gx)", state, formatReplayTime(r.time), formatReplayTime(r.duration()), r.speed)
+}
+
+func formatReplayTime(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (r *replayScreen) update() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		r.game.HandleGameEnded()
+		r.client.currentScreen = newReplayListScreen(r.client)
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		r.paused = !r.paused
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft):
+		r.seek(r.time - replaySeekStep)
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		r.seek(r.time + replaySeekStep)
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		if r.speed < replayMaxSpeed {
+			r.speed *= 2
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		if r.speed > replayMinSpeed {
+			r.speed /= 2
+		}
+	}
+
+	// Ein game.ResultGame wird auch nach dem Ende weiter aktualisiert, damit es das Ergebnis animieren kann
+	if !r.paused && (r.time < r.duration() || r.finished) {
+		r.seek(r.time + time.Duration(r.speed*float64(time.Second)/float64(ebiten.MaxTPS())))
+		r.game.Update()
+	}
+
+	if resultGame, ok := r.game.(game.ResultGame); ok && !r.finished && r.time >= r.duration() &&
+		len(r.replay.Result.Ranking) != 0 {
+		r.finished = true
+		resultGame.HandleResult(r.replay.Result)
+	}
+
+	r.status.Text = r.statusText()
+}
+
+func (r *replayScreen) draw(screen *ebiten.Image) {
+	r.game.Draw(screen)
+	r.status.Draw(screen)
+}
+
+func (r *replayScreen) layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return r.game.Layout(outsideWidth, outsideHeight)
+}
@@ -9,7 +9,33 @@ import (
 
 var borderColor = colornames.Black
 
-func drawBorders(board *ebiten.Image) {
+// grid speichert für jedes Feld des Spielfeldes einen Wahrheitswert, grid[x][y].
+type grid [][]bool
+
+func newGrid(rules shared.Rules) grid {
+	result := make(grid, rules.Width)
+	for x := range result {
+		result[x] = make([]bool, rules.Height)
+	}
+	return result
+}
+
+// boardWidth gibt die Breite des Spielfeldes in Pixeln zurück.
+func boardWidth(rules shared.Rules) int {
+	return rules.Width*fieldSize + (rules.Width+1)*borderWidth
+}
+
+// boardHeight gibt die Höhe des Spielfeldes in Pixeln zurück.
+func boardHeight(rules shared.Rules) int {
+	return rules.Height*fieldSize + (rules.Height+1)*borderWidth
+}
+
+func newBoardImage(rules shared.Rules) *ebiten.Image {
+	return ebiten.NewImage(boardWidth(rules), boardHeight(rules))
+}
+
+func drawBorders(board *ebiten.Image, rules shared.Rules) {
+	boardWidth, boardHeight := boardWidth(rules), boardHeight(rules)
 	width, height := board.Size()
 	if width != boardWidth || height != boardHeight {
 		panic("invalid board size")
@@ -19,7 +45,7 @@ func drawBorders(board *ebiten.Image) {
 	board.ReadPixels(pixels)
 
 	// Horizontal
-	for borderIndexY := 0; borderIndexY < numberOfHorizontalBorders; borderIndexY++ {
+	for borderIndexY := 0; borderIndexY < rules.Height+1; borderIndexY++ {
 		y := borderIndexY * (fieldSize + borderWidth)
 		for x := 0; x < boardWidth; x++ {
 			startIndex := (y*boardWidth + x) * 4
@@ -32,7 +58,7 @@ func drawBorders(board *ebiten.Image) {
 	}
 
 	// Vertikal
-	for borderIndexX := 0; borderIndexX < numberOfVerticalBorders; borderIndexX++ {
+	for borderIndexX := 0; borderIndexX < rules.Width+1; borderIndexX++ {
 		x := borderIndexX * (fieldSize + borderWidth)
 		for y := 0; y < boardHeight; y++ {
 			startIndex := (y*boardWidth + x) * 4
@@ -58,16 +84,16 @@ var (
 		img.Fill(colornames.Green)
 		return img
 	})
+	sunkFieldImg = lazy.New(func() *ebiten.Image {
+		img := ebiten.NewImage(fieldSize, fieldSize)
+		img.Fill(colornames.Darkred)
+		return img
+	})
 )
 
-func drawShips(board *ebiten.Image, ships [shared.BoardWidth][shared.BoardHeight]bool) {
-	width, height := board.Size()
-	if width != boardWidth || height != boardHeight {
-		panic("invalid board size")
-	}
-
-	for x := 0; x < shared.BoardWidth; x++ {
-		for y := 0; y < shared.BoardHeight; y++ {
+func drawShips(board *ebiten.Image, ships grid) {
+	for x := range ships {
+		for y := range ships[x] {
 			var fieldImg *ebiten.Image
 			switch ships[x][y] {
 			case false:
@@ -115,14 +141,24 @@ var (
 	})
 )
 
-func drawMarkers(board *ebiten.Image, markers [shared.BoardWidth][shared.BoardHeight]bool) {
-	width, height := board.Size()
-	if width != boardWidth || height != boardHeight {
-		panic("invalid board size")
+// drawSunkShips färbt die Felder versenkter Schiffe ein.
+func drawSunkShips(board *ebiten.Image, sunk grid) {
+	for x := range sunk {
+		for y := range sunk[x] {
+			if !sunk[x][y] {
+				continue
+			}
+
+			var fieldImgDrawOptions ebiten.DrawImageOptions
+			fieldImgDrawOptions.GeoM.Translate(float64(x*fieldSize+(x+1)*borderWidth), float64(y*fieldSize+(y+1)*borderWidth))
+			board.DrawImage(sunkFieldImg(), &fieldImgDrawOptions)
+		}
 	}
+}
 
-	for x := 0; x < shared.BoardWidth; x++ {
-		for y := 0; y < shared.BoardHeight; y++ {
+func drawMarkers(board *ebiten.Image, markers grid) {
+	for x := range markers {
+		for y := range markers[x] {
 			if !markers[x][y] {
 				continue
 			}
@@ -134,11 +170,15 @@ func drawMarkers(board *ebiten.Image, markers [shared.BoardWidth][shared.BoardHe
 	}
 }
 
-func getFieldCoordinates(mouseX, mouseY int) (int, int, bool) {
+func getFieldCoordinates(rules shared.Rules, mouseX, mouseY int) (int, int, bool) {
+	if mouseX < 0 || mouseY < 0 {
+		return 0, 0, false
+	}
+
 	fieldX := mouseX / (borderWidth + fieldSize)
 	fieldY := mouseY / (borderWidth + fieldSize)
 
-	if fieldX < 0 || fieldX >= shared.BoardWidth || fieldY < 0 || fieldY >= shared.BoardHeight {
+	if !(shared.Position{X: fieldX, Y: fieldY}).Valid(rules) {
 		return 0, 0, false
 	}
 
@@ -10,30 +10,35 @@ import (
 
 type enemyBoard struct {
 	game    *impl
-	ships   [shared.BoardWidth][shared.BoardHeight]bool
-	markers [shared.BoardWidth][shared.BoardHeight]bool
+	ships   grid
+	sunk    grid
+	markers grid
 }
 
 func newEmptyEnemyBoard(game *impl) *enemyBoard {
 	return &enemyBoard{
-		game: game,
+		game:    game,
+		ships:   newGrid(game.rules),
+		sunk:    newGrid(game.rules),
+		markers: newGrid(game.rules),
 	}
 }
 
 func (e *enemyBoard) draw(screen *ebiten.Image) {
-	board := ebiten.NewImage(boardWidth, boardHeight)
-	drawBorders(board)
+	board := newBoardImage(e.game.rules)
+	drawBorders(board, e.game.rules)
 	drawShips(board, e.ships)
+	drawSunkShips(board, e.sunk)
 	drawMarkers(board, e.markers)
 	var boardDrawOptions ebiten.DrawImageOptions
-	boardDrawOptions.GeoM.Translate(boardWidth+distanceBetweenBoards, 0)
+	boardDrawOptions.GeoM.Translate(float64(boardWidth(e.game.rules)+distanceBetweenBoards), 0)
 	screen.DrawImage(board, &boardDrawOptions)
 }
 
 func (e *enemyBoard) update() {
 	mouseX, mouseY := ebiten.CursorPosition()
 
-	fieldX, fieldY, ok := getFieldCoordinates(mouseX-boardWidth-distanceBetweenBoards, mouseY)
+	fieldX, fieldY, ok := getFieldCoordinates(e.game.rules, mouseX-boardWidth(e.game.rules)-distanceBetweenBoards, mouseY)
 	if !ok {
 		return
 	}
@@ -42,7 +47,8 @@ func (e *enemyBoard) update() {
 		e.markers[fieldX][fieldY] = !e.markers[fieldX][fieldY]
 	}
 
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && e.game.turnIndicator.IsMyTurn() &&
+		e.game.result == nil {
 		fire, err := json.Marshal(shared.FirePacket{
 			PacketName: shared.FirePacketName,
 			Position:   shared.Position{X: fieldX, Y: fieldY},
@@ -60,4 +66,29 @@ func (e *enemyBoard) handleFireResultPacket(packet shared.FireResultPacket) {
 	if packet.Hit {
 		e.ships[packet.Position.X][packet.Position.Y] = true
 	}
+
+	if packet.Sunk {
+		e.markSunk(packet.Ship)
+	}
+}
+
+// markSunk markiert ein versenktes Schiff. Da sich Schiffe nicht berühren dürfen, kann um das Schiff herum kein
+// anderes Schiff liegen, deshalb werden diese Felder auch markiert.
+func (e *enemyBoard) markSunk(ship shared.Ship) {
+	for pos := range ship.BlockedFields(e.game.rules) {
+		e.markers[pos.X][pos.Y] = true
+	}
+	for _, pos := range ship {
+		e.ships[pos.X][pos.Y] = true
+		e.sunk[pos.X][pos.Y] = true
+	}
+}
+
+// reveal zeigt am Ende des Spieles die Schiffe des Gegners an, die nicht getroffen wurden.
+func (e *enemyBoard) reveal(ships shared.Ships) {
+	for _, ship := range ships {
+		for _, pos := range ship {
+			e.ships[pos.X][pos.Y] = true
+		}
+	}
 }
@@ -6,14 +6,18 @@ import (
 )
 
 type personalBoard struct {
-	game  *impl
-	ships [shared.BoardWidth][shared.BoardHeight]bool
-	hits  [shared.BoardWidth][shared.BoardHeight]bool
+	game      *impl
+	shipsList shared.Ships
+	ships     grid
+	hits      grid
 }
 
 func newPersonalBoard(game *impl, ships shared.Ships) *personalBoard {
 	board := personalBoard{
-		game: game,
+		game:      game,
+		shipsList: ships,
+		ships:     newGrid(game.rules),
+		hits:      newGrid(game.rules),
 	}
 
 	for _, ship := range ships {
@@ -29,10 +33,32 @@ func (p *personalBoard) handleOponentFiredPacket(packet shared.OpponentFiredPack
 	p.hits[packet.Position.X][packet.Position.Y] = true
 }
 
+// sunk gibt die Felder der eigenen Schiffe zurück, die vollständig getroffen wurden.
+func (p *personalBoard) sunk() grid {
+	sunk := newGrid(p.game.rules)
+	for _, ship := range p.shipsList {
+		isSunk := true
+		for _, field := range ship {
+			if !p.hits[field.X][field.Y] {
+				isSunk = false
+			}
+		}
+		if !isSunk {
+			continue
+		}
+
+		for _, field := range ship {
+			sunk[field.X][field.Y] = true
+		}
+	}
+	return sunk
+}
+
 func (p *personalBoard) draw(screen *ebiten.Image) {
-	board := ebiten.NewImage(boardWidth, boardHeight)
-	drawBorders(board)
+	board := newBoardImage(p.game.rules)
+	drawBorders(board, p.game.rules)
 	drawShips(board, p.ships)
+	drawSunkShips(board, p.sunk())
 	drawMarkers(board, p.hits)
 	screen.DrawImage(board, nil)
 }
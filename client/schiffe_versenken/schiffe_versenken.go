@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Lama06/Oinky-Party/client/game"
 	"github.com/Lama06/Oinky-Party/client/ui"
@@ -14,31 +15,54 @@ import (
 )
 
 const (
-	fieldSize                 = 50
-	borderWidth               = 1
-	distanceBetweenBoards     = 80
-	numberOfHorizontalBorders = shared.BoardHeight + 1
-	numberOfVerticalBorders   = shared.BoardWidth + 1
-	boardWidth                = shared.BoardWidth*fieldSize + numberOfVerticalBorders*borderWidth
-	boardHeight               = shared.BoardHeight*fieldSize + numberOfHorizontalBorders*borderWidth
+	fieldSize             = 50
+	borderWidth           = 1
+	distanceBetweenBoards = 80
+	turnIndicatorHeight   = 60 // Unter den Spielfeldern wird angezeigt, wer am Zug ist
+	notificationDuration  = 2 * time.Second
+	setupSidebarWidth     = 250 // Neben dem Spielfeld zum Aufstellen stehen die übrigen Schiffe und der Knopf zum Fortfahren
 )
 
 type impl struct {
 	client                    game.Client
+	rules                     shared.Rules
 	setupShipsContinueBtn     *ui.Button
 	setupBoard                *setupBoard
+	setupTimeLeft             int32 // In Millisekunden, -1, wenn die Zeit zum Aufstellen nicht begrenzt ist
+	setupTimeReceivedAt       time.Time
+	setupTimeText             *ui.Text
 	hasSetupShips             bool
 	waitingForGameToStartText *ui.Text
+	spectatingText            *ui.Text
 	gameStarted               bool
 	personalBoard             *personalBoard
 	enemyBoard                *enemyBoard
+	turnIndicator             *game.TurnIndicator
+	notifications             [2]*notification         // Unter dem eigenen und unter dem gegnerischen Spielfeld
+	result                    *protocol.GameResultData // nil, solange das Spiel läuft
+	resultText                *ui.Text
 }
 
-var _ game.Game = (*impl)(nil)
+// notification ist ein Hinweis, der für kurze Zeit unter einem Spielfeld angezeigt wird.
+type notification struct {
+	text  *ui.Text
+	ticks int // Wie lange der Hinweis noch angezeigt wird
+}
+
+const (
+	personalNotification = iota
+	enemyNotification
+)
+
+var _ game.SnapshotGame = (*impl)(nil)
+var _ game.ResultGame = (*impl)(nil)
 
 func create(client game.Client) game.Game {
 	return &impl{
 		client: client,
+		// Bis zum shared.RulesPacket werden die Standardregeln verwendet
+		rules:         shared.RulesFromOptions(shared.Options.Defaults()),
+		setupTimeLeft: -1,
 	}
 }
 
@@ -46,13 +70,111 @@ var _ game.Creator = create
 
 func (i *impl) HandleGameStarted() {
 	i.setupShipsContinueBtn = i.createSetupSetupShipsContinueBtn()
-	i.setupBoard = newEmptySetupBoard(i)
 	i.waitingForGameToStartText = i.createWaitingForGameToStartText()
+	i.spectatingText = i.createSpectatingText()
+	i.setupTimeText = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(_, _ int) ui.Position {
+			return ui.TopLeftCornerPosition{
+				X: boardWidth(i.rules) + remainingShipsRowHeight/2,
+				Y: remainingShipsRowHeight/2 + (len(i.rules.Fleet)+1)*remainingShipsRowHeight + 8,
+			}
+		}),
+	})
+	i.turnIndicator = game.NewTurnIndicator(i.client, ui.DynamicPosition(func(width, height int) ui.Position {
+		return ui.CenteredPosition{X: width / 2, Y: boardHeight(i.rules) + turnIndicatorHeight/2}
+	}))
+	for board := range i.notifications {
+		board := board
+		i.notifications[board] = &notification{
+			text: ui.NewText(ui.TextConfig{
+				Pos: ui.DynamicPosition(func(_, _ int) ui.Position {
+					x := boardWidth(i.rules) / 2
+					if board == enemyNotification {
+						x += boardWidth(i.rules) + distanceBetweenBoards
+					}
+					return ui.CenteredPosition{X: x, Y: boardHeight(i.rules) + turnIndicatorHeight/2}
+				}),
+				Colors: &ui.TextColorPalette{Color: colornames.Darkred},
+			}),
+		}
+	}
+	i.resultText = ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			if !i.gameStarted {
+				return ui.CenteredPosition{X: width / 2, Y: height / 2}
+			}
+			return ui.CenteredPosition{X: width / 2, Y: boardHeight(i.rules) + turnIndicatorHeight/2}
+		}),
+	})
+	i.setRules(i.rules)
+}
+
+// notify zeigt einen Hinweis unter einem Spielfeld an.
+func (i *impl) notify(board int, text string) {
+	i.notifications[board].text.Text = text
+	i.notifications[board].ticks = int(notificationDuration.Seconds() * float64(ebiten.MaxTPS()))
+}
+
+// setRules legt die Regeln fest und leert die Spielfelder, deren Größe von den Regeln abhängt.
+func (i *impl) setRules(rules shared.Rules) {
+	i.rules = rules
+	i.setupBoard = newEmptySetupBoard(i)
 	i.enemyBoard = newEmptyEnemyBoard(i)
 }
 
+// setSetupTime legt fest, wie viele Millisekunden noch Zeit ist, die Schiffe aufzustellen.
+func (i *impl) setSetupTime(milliseconds int32) {
+	i.setupTimeLeft = milliseconds
+	i.setupTimeReceivedAt = time.Now()
+}
+
+// updateSetupTime zeigt an, wie viel Zeit noch bleibt, bevor die Schiffe zufällig aufgestellt werden.
+func (i *impl) updateSetupTime() {
+	if i.setupTimeLeft < 0 {
+		i.setupTimeText.Text = ""
+		return
+	}
+
+	left := time.Duration(i.setupTimeLeft)*time.Millisecond - time.Since(i.setupTimeReceivedAt)
+	if left < 0 {
+		left = 0
+	}
+	seconds := int(left.Round(time.Second) / time.Second)
+	i.setupTimeText.Text = fmt.Sprintf("Zeit: %d:%02d", seconds/60, seconds%60)
+}
+
 func (i *impl) HandleGameEnded() {}
 
+func (i *impl) HandleResult(result protocol.GameResultData) {
+	i.result = &result
+
+	var details shared.ResultDetails
+	if len(result.Details) != 0 && json.Unmarshal(result.Details, &details) == nil {
+		for id, ships := range details.Ships {
+			if id != i.client.Id() && ships.Valid(i.rules) {
+				i.enemyBoard.reveal(ships)
+			}
+		}
+	}
+
+	var winner int32
+	if len(result.Ranking) != 0 && len(result.Ranking[0]) == 1 {
+		winner = result.Ranking[0][0]
+	}
+	switch {
+	case i.client.Spectating():
+		if player, ok := i.client.GamePlayers()[winner]; ok {
+			i.resultText.Text = player.Name + " hat gewonnen"
+		} else {
+			i.resultText.Text = "Das Spiel ist vorbei"
+		}
+	case winner == i.client.Id():
+		i.resultText.Text = "Du hast gewonnen"
+	default:
+		i.resultText.Text = "Du hast verloren"
+	}
+}
+
 func (i *impl) HandlePacket(data []byte) error {
 	packetName, err := protocol.GetPacketName(data)
 	if err != nil {
@@ -60,6 +182,52 @@ func (i *impl) HandlePacket(data []byte) error {
 	}
 
 	switch packetName {
+	case shared.RulesPacketName:
+		if i.hasSetupShips {
+			return errors.New("received rules after the ships were set up")
+		}
+
+		var rules shared.RulesPacket
+		err := json.Unmarshal(data, &rules)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if !rules.Rules.Valid() {
+			return fmt.Errorf("invalid rules: %+v", rules.Rules)
+		}
+
+		i.setRules(rules.Rules)
+		return nil
+	case shared.SetupTimePacketName:
+		var setupTime shared.SetupTimePacket
+		err := json.Unmarshal(data, &setupTime)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		i.setSetupTime(setupTime.SetupTimeLeft)
+		return nil
+	case shared.ShipsPlacedPacketName:
+		if i.hasSetupShips {
+			return errors.New("received ships after the ships were set up")
+		}
+
+		var shipsPlaced shared.ShipsPlacedPacket
+		err := json.Unmarshal(data, &shipsPlaced)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if !shipsPlaced.Ships.Valid(i.rules) {
+			return errors.New("invalid ships")
+		}
+
+		// Die Zeit zum Aufstellen ist abgelaufen, deshalb hat der Server die Schiffe zufällig aufgestellt
+		i.hasSetupShips = true
+		i.personalBoard = newPersonalBoard(i, shipsPlaced.Ships)
+		i.notify(personalNotification, "Schiffe zufällig aufgestellt")
+		return nil
 	case shared.GameStartedPacketName:
 		if !i.hasSetupShips {
 			return errors.New("game started before player set up their ships")
@@ -78,13 +246,22 @@ func (i *impl) HandlePacket(data []byte) error {
 			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
 
-		if !fireResult.Position.Valid() {
+		if !fireResult.Position.Valid(i.rules) {
 			return errors.New("invalid position")
 		}
 
+		if fireResult.Sunk && !fireResult.Ship.Valid(i.rules) {
+			return errors.New("invalid ship")
+		}
+
 		i.enemyBoard.handleFireResultPacket(fireResult)
+		if fireResult.Sunk {
+			i.notify(enemyNotification, "Schiff versenkt!")
+		}
 
 		return nil
+	case protocol.TurnChangedPacketName:
+		return i.turnIndicator.HandleTurnChangedPacket(data)
 	case shared.OpponentFiredPacketName:
 		if !i.gameStarted {
 			return errors.New("game has not started yet")
@@ -96,11 +273,14 @@ func (i *impl) HandlePacket(data []byte) error {
 			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
 
-		if !opponentFired.Position.Valid() {
+		if !opponentFired.Position.Valid(i.rules) {
 			return errors.New("invalid position")
 		}
 
 		i.personalBoard.handleOponentFiredPacket(opponentFired)
+		if opponentFired.Sunk {
+			i.notify(personalNotification, "Schiff verloren!")
+		}
 
 		return nil
 	default:
@@ -108,50 +288,145 @@ func (i *impl) HandlePacket(data []byte) error {
 	}
 }
 
+func (i *impl) ApplySnapshot(data []byte) error {
+	var snapshot shared.Snapshot
+	err := json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	if !snapshot.Rules.Valid() {
+		return fmt.Errorf("invalid rules: %+v", snapshot.Rules)
+	}
+	i.setRules(snapshot.Rules)
+	i.setSetupTime(snapshot.SetupTimeLeft)
+
+	if snapshot.Ships == nil {
+		return nil
+	}
+
+	if !snapshot.Ships.Valid(i.rules) {
+		return errors.New("invalid ships")
+	}
+
+	i.hasSetupShips = true
+	i.personalBoard = newPersonalBoard(i, snapshot.Ships)
+
+	if !snapshot.Started {
+		return nil
+	}
+
+	i.gameStarted = true
+	for _, shot := range snapshot.Shots {
+		if !shot.Position.Valid(i.rules) {
+			return errors.New("invalid position")
+		}
+		i.enemyBoard.handleFireResultPacket(shared.FireResultPacket{Position: shot.Position, Hit: shot.Hit})
+	}
+	for _, ship := range snapshot.SunkShips {
+		if !ship.Valid(i.rules) {
+			return errors.New("invalid ship")
+		}
+		i.enemyBoard.markSunk(ship)
+	}
+	for _, pos := range snapshot.OpponentShots {
+		if !pos.Valid(i.rules) {
+			return errors.New("invalid position")
+		}
+		i.personalBoard.handleOponentFiredPacket(shared.OpponentFiredPacket{Position: pos})
+	}
+	i.turnIndicator.SetTurn(snapshot.Turn)
+
+	return nil
+}
+
 func (i *impl) Draw(screen *ebiten.Image) {
 	screen.Fill(colornames.White)
 
-	if !i.hasSetupShips {
+	if i.result != nil && (i.client.Spectating() || !i.gameStarted) {
+		// Zuschauer sehen nur das Ergebnis. Für die Spieler wurde das Spiel beendet, bevor beide ihre Schiffe
+		// aufgestellt hatten.
+		i.resultText.Draw(screen)
+	} else if i.client.Spectating() {
+		i.spectatingText.Draw(screen)
+	} else if !i.hasSetupShips {
 		i.setupBoard.draw(screen)
+		i.setupBoard.drawRemainingShips(screen)
+		i.setupTimeText.Draw(screen)
 		i.setupShipsContinueBtn.Draw(screen)
 	} else if !i.gameStarted {
 		i.waitingForGameToStartText.Draw(screen)
 	} else {
 		i.personalBoard.draw(screen)
 		i.enemyBoard.draw(screen)
+		for _, notification := range i.notifications {
+			if notification.ticks > 0 {
+				notification.text.Draw(screen)
+			}
+		}
+		if i.result != nil {
+			i.resultText.Draw(screen)
+		} else {
+			i.turnIndicator.Draw(screen)
+		}
 	}
 }
 
 func (i *impl) Update() {
-	if !i.hasSetupShips {
+	for _, notification := range i.notifications {
+		if notification.ticks > 0 {
+			notification.ticks--
+		}
+	}
+
+	if i.client.Spectating() {
+		i.spectatingText.Update()
+	} else if i.result != nil && !i.gameStarted {
+		return
+	} else if !i.hasSetupShips {
 		i.setupBoard.update()
-		switch i.setupBoard.parseShips().Valid() {
+		switch i.setupBoard.parseShips().Valid(i.rules) {
 		case false:
 			i.setupShipsContinueBtn.SetColors(&ui.DisabledButtonColors)
 		case true:
 			i.setupShipsContinueBtn.SetColors(&ui.ButtonColors)
 		}
 		i.setupShipsContinueBtn.Update()
+		i.updateSetupTime()
 	} else if !i.gameStarted {
 		i.waitingForGameToStartText.Update()
 	} else {
+		i.turnIndicator.Update()
 		i.enemyBoard.update()
 	}
 }
 
 func (i *impl) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	if !i.hasSetupShips {
-		return boardWidth + 200, boardHeight
+	if i.client.Spectating() || (i.result != nil && !i.gameStarted) {
+		return outsideWidth, outsideHeight
+	} else if !i.hasSetupShips {
+		return boardWidth(i.rules) + setupSidebarWidth, i.setupHeight()
 	} else if !i.gameStarted {
 		return outsideWidth, outsideHeight
 	} else {
-		return boardWidth*2 + distanceBetweenBoards, boardHeight
+		return boardWidth(i.rules)*2 + distanceBetweenBoards, boardHeight(i.rules) + turnIndicatorHeight
+	}
+}
+
+// setupHeight gibt die Höhe des Bildschirmes zum Aufstellen der Schiffe zurück. Die Liste der übrigen Schiffe kann
+// höher als das Spielfeld sein.
+func (i *impl) setupHeight() int {
+	if height := remainingShipsHeight(i.rules); height > boardHeight(i.rules) {
+		return height
 	}
+	return boardHeight(i.rules)
 }
 
 func (i *impl) createSetupSetupShipsContinueBtn() *ui.Button {
 	return ui.NewButton(ui.ButtonConfig{
-		Pos:  ui.CenteredPosition{X: boardWidth + 100, Y: boardHeight / 2},
+		Pos: ui.DynamicPosition(func(_, _ int) ui.Position {
+			return ui.CenteredPosition{X: boardWidth(i.rules) + setupSidebarWidth/2, Y: i.setupHeight() - continueButtonHeight/2}
+		}),
 		Text: "Weiter",
 		Callback: func() {
 			if i.hasSetupShips {
@@ -160,7 +435,7 @@ func (i *impl) createSetupSetupShipsContinueBtn() *ui.Button {
 
 			ships := i.setupBoard.parseShips()
 
-			if !ships.Valid() {
+			if !ships.Valid(i.rules) {
 				return
 			}
 
@@ -188,8 +463,32 @@ func (i *impl) createWaitingForGameToStartText() *ui.Text {
 	})
 }
 
+func (i *impl) createSpectatingText() *ui.Text {
+	return ui.NewText(ui.TextConfig{
+		Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+			return ui.CenteredPosition{X: width / 2, Y: height / 2}
+		}),
+		Text: "Du schaust zu",
+	})
+}
+
+// checkOptions meldet, wenn die gewählte Flotte leer ist oder nicht auf das Spielfeld passt. Der Server würde das Spiel
+// sonst nicht starten.
+func checkOptions(options protocol.OptionValues) string {
+	rules := shared.RulesFromOptions(options)
+	if rules.Valid() {
+		return ""
+	}
+	if len(rules.Fleet) == 0 {
+		return "Die eigene Flotte hat keine Schiffe"
+	}
+	return fmt.Sprintf("Die Flotte passt nicht auf ein %dx%d großes Spielfeld", rules.Width, rules.Height)
+}
+
 var Type = game.Type{
-	Name:        shared.Name,
-	DisplayName: "Schiffe versenken",
-	Creator:     create,
+	Name:         shared.Name,
+	DisplayName:  "Schiffe versenken",
+	Version:      shared.Version,
+	Creator:      create,
+	CheckOptions: checkOptions,
 }
@@ -1,19 +1,36 @@
 package schiffe_versenken
 
 import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/Lama06/Oinky-Party/client/ui"
 	shared "github.com/Lama06/Oinky-Party/schiffe_versenken"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/colornames"
+)
+
+const (
+	remainingShipsRowHeight = 40
+	remainingShipsFieldSize = 14 // Die Größe eines Feldes der kleinen Schiffe in der Liste der übrigen Schiffe
+	maxRemainingShipsLength = 5  // Längere Schiffe werden in der Liste gekürzt
+	continueButtonHeight    = 80
 )
 
 type setupBoard struct {
-	game  *impl
-	ships [shared.BoardWidth][shared.BoardHeight]bool
+	game               *impl
+	ships              grid
+	remainingShipsText *ui.Text // Wird für jede Zeile der Liste der übrigen Schiffe neu beschriftet
 }
 
 func newEmptySetupBoard(game *impl) *setupBoard {
 	return &setupBoard{
-		game: game,
+		game:               game,
+		ships:              newGrid(game.rules),
+		remainingShipsText: ui.NewText(ui.TextConfig{}),
 	}
 }
 
@@ -24,7 +41,7 @@ const (
 	verticalParseDirection
 )
 
-func (p parseShipsDirection) nextPosition(position shared.Position) (shared.Position, bool) {
+func (p parseShipsDirection) nextPosition(rules shared.Rules, position shared.Position) (shared.Position, bool) {
 	next := position
 	switch p {
 	case horizontalParseDirection:
@@ -32,7 +49,7 @@ func (p parseShipsDirection) nextPosition(position shared.Position) (shared.Posi
 	case verticalParseDirection:
 		next.Y++
 	}
-	return next, next.Valid()
+	return next, next.Valid(rules)
 }
 
 func (s *setupBoard) parseShipsInLine(
@@ -51,7 +68,7 @@ func (s *setupBoard) parseShipsInLine(
 			currentShip = nil
 		}
 
-		nextPosition, ok := direction.nextPosition(currentPosition)
+		nextPosition, ok := direction.nextPosition(s.game.rules, currentPosition)
 		if !ok {
 			break
 		}
@@ -64,29 +81,29 @@ func (s *setupBoard) parseShipsInLine(
 }
 
 func (s *setupBoard) parseHorizontalShips() (ships []shared.Ship) {
-	for y := 0; y < shared.BoardHeight; y++ {
+	for y := 0; y < s.game.rules.Height; y++ {
 		ships = append(ships, s.parseShipsInLine(shared.Position{X: 0, Y: y}, horizontalParseDirection)...)
 	}
 	return
 }
 
 func (s *setupBoard) parseVerticalShips() (ships []shared.Ship) {
-	for x := 0; x < shared.BoardWidth; x++ {
+	for x := 0; x < s.game.rules.Width; x++ {
 		ships = append(ships, s.parseShipsInLine(shared.Position{X: x, Y: 0}, verticalParseDirection)...)
 	}
 	return
 }
 
 func (s *setupBoard) parseSingleFieldShips() (ships []shared.Ship) {
-	for x := 0; x < shared.BoardWidth; x++ {
+	for x := 0; x < s.game.rules.Width; x++ {
 	yLoop:
-		for y := 0; y < shared.BoardHeight; y++ {
+		for y := 0; y < s.game.rules.Height; y++ {
 			if !s.ships[x][y] {
 				continue
 			}
 
 			position := shared.Position{X: x, Y: y}
-			for neighbour := range position.Neighbours() {
+			for neighbour := range position.Neighbours(s.game.rules) {
 				if s.ships[neighbour.X][neighbour.Y] {
 					continue yLoop
 				}
@@ -112,14 +129,15 @@ func (s *setupBoard) parseShips() (ships shared.Ships) {
 }
 
 func (s *setupBoard) draw(screen *ebiten.Image) {
-	board := ebiten.NewImage(boardWidth, boardHeight)
-	drawBorders(board)
+	board := newBoardImage(s.game.rules)
+	drawBorders(board, s.game.rules)
 	drawShips(board, s.ships)
 	screen.DrawImage(board, nil)
 }
 
 func (s *setupBoard) update() {
-	fieldX, fieldY, ok := getFieldCoordinates(ebiten.CursorPosition())
+	mouseX, mouseY := ebiten.CursorPosition()
+	fieldX, fieldY, ok := getFieldCoordinates(s.game.rules, mouseX, mouseY)
 	if !ok {
 		return
 	}
@@ -128,3 +146,71 @@ func (s *setupBoard) update() {
 		s.ships[fieldX][fieldY] = !s.ships[fieldX][fieldY]
 	}
 }
+
+// remainingShipsHeight gibt die Höhe der Liste der übrigen Schiffe mit dem Knopf zum Fortfahren zurück. Unter den
+// Längen der Flotte ist Platz für eine Zeile mit den Schiffen, deren Länge es in der Flotte nicht gibt, und für die
+// verbleibende Zeit zum Aufstellen.
+func remainingShipsHeight(rules shared.Rules) int {
+	return (len(rules.Fleet)+2)*remainingShipsRowHeight + remainingShipsRowHeight/2 + continueButtonHeight
+}
+
+// drawRemainingShips zeigt neben dem Spielfeld für jede Länge an, wie viele Schiffe noch aufgestellt werden müssen.
+func (s *setupBoard) drawRemainingShips(screen *ebiten.Image) {
+	ships := s.parseShips()
+
+	lengths := make([]int, 0, len(s.game.rules.Fleet))
+	for length := range s.game.rules.Fleet {
+		lengths = append(lengths, length)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+
+	x := boardWidth(s.game.rules) + remainingShipsRowHeight/2
+	textX := x + maxRemainingShipsLength*(remainingShipsFieldSize+2) + 10
+	for row, length := range lengths {
+		y := remainingShipsRowHeight/2 + row*remainingShipsRowHeight
+		remaining := s.game.rules.Fleet[length] - ships.CountShipsWithLength(length)
+
+		var clr color.Color
+		switch {
+		case remaining == 0:
+			clr = colornames.Green
+			s.remainingShipsText.Text = "fertig"
+		case remaining > 0:
+			clr = colornames.Black
+			s.remainingShipsText.Text = fmt.Sprintf("noch %d", remaining)
+		default:
+			clr = colornames.Red
+			s.remainingShipsText.Text = fmt.Sprintf("%d zu viel", -remaining)
+		}
+
+		for field := 0; field < length && field < maxRemainingShipsLength; field++ {
+			ebitenutil.DrawRect(
+				screen,
+				float64(x+field*(remainingShipsFieldSize+2)), float64(y+(remainingShipsRowHeight-remainingShipsFieldSize)/2),
+				remainingShipsFieldSize, remainingShipsFieldSize,
+				clr,
+			)
+		}
+
+		s.remainingShipsText.Colors = &ui.TextColorPalette{Color: clr}
+		s.remainingShipsText.Pos = ui.TopLeftCornerPosition{X: textX, Y: y + 8}
+		s.remainingShipsText.Draw(screen)
+	}
+
+	// Schiffe, deren Länge es in der Flotte nicht gibt, müssen entfernt werden
+	others := 0
+	for _, ship := range ships {
+		if _, ok := s.game.rules.Fleet[len(ship)]; !ok {
+			others++
+		}
+	}
+	if others != 0 {
+		s.remainingShipsText.Text = fmt.Sprintf("%d falsche Länge", others)
+		s.remainingShipsText.Colors = &ui.TextColorPalette{Color: colornames.Red}
+		s.remainingShipsText.Pos = ui.TopLeftCornerPosition{
+			X: x,
+			Y: remainingShipsRowHeight/2 + len(lengths)*remainingShipsRowHeight + 8,
+		}
+		s.remainingShipsText.Draw(screen)
+	}
+}
@@ -13,3 +13,9 @@ type packetHandlerScreen interface {
 	screen
 	handlePacket(packet []byte) error
 }
+
+// layoutScreen bestimmt die Größe des Bildschirms selbst, zum Beispiel bei der Wiedergabe eines Spieles.
+type layoutScreen interface {
+	screen
+	layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
+}
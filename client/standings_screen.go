@@ -0,0 +1,90 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/Lama06/Oinky-Party/client/rescources"
+	"github.com/Lama06/Oinky-Party/client/ui"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type standingsScreen struct {
+	client         *client
+	title          *ui.Text
+	standings      []*ui.Text
+	continueButton *ui.Button
+}
+
+var _ screen = (*standingsScreen)(nil)
+
+func newStandingsScreen(client *client, packet protocol.StandingsPacket) *standingsScreen {
+	standings := make([]*ui.Text, len(packet.Standings))
+	place := 0
+	for i, standing := range packet.Standings {
+		iCopy := i
+
+		// Spieler mit gleich vielen Punkten teilen sich einen Platz
+		if i == 0 || packet.Standings[i-1].Points != standing.Points {
+			place = i + 1
+		}
+
+		standings[i] = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: 100 + height/3 + 60*iCopy}
+			}),
+			Text: fmt.Sprintf("%d. %s: %d Punkte", place, standing.Player.Name, standing.Points),
+		})
+	}
+
+	return &standingsScreen{
+		client: client,
+		title: ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height / 3}
+			}),
+			Text:   "Endstand",
+			Colors: &ui.TitleColors,
+			Font:   rescources.RobotoTitleFont,
+		}),
+		standings: standings,
+		continueButton: ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: height - 100}
+			}),
+			Text: "Weiter",
+			Callback: func() {
+				client.currentScreen = newPartyScreen(client)
+			},
+		}),
+	}
+}
+
+func (s *standingsScreen) components() []ui.Component {
+	components := []ui.Component{s.title, s.continueButton}
+
+	for _, standing := range s.standings {
+		components = append(components, standing)
+	}
+
+	return components
+}
+
+func (s *standingsScreen) update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.client.currentScreen = newPartyScreen(s.client)
+		return
+	}
+
+	for _, component := range s.components() {
+		component.Update()
+	}
+}
+
+func (s *standingsScreen) draw(screen *ebiten.Image) {
+	screen.Fill(ui.BackgroundColor)
+	for _, component := range s.components() {
+		component.Draw(screen)
+	}
+}
@@ -1,45 +1,61 @@
 package client
 
 import (
-	"encoding/json"
-
 	"github.com/Lama06/Oinky-Party/client/rescources"
 	"github.com/Lama06/Oinky-Party/client/ui"
-	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type startGameScreen struct {
-	client      *client
-	title       *ui.Text
-	gameButtons []*ui.Button
+	client           *client
+	title            *ui.Text
+	gameButtons      []*ui.Button
+	gameDescriptions []*ui.Text
 }
 
 var _ screen = (*startGameScreen)(nil)
 
 func newStartGameScreen(client *client) *startGameScreen {
+	gameTypes := client.availableGameTypes()
+
 	gameButtons := make([]*ui.Button, len(gameTypes))
+	gameDescriptions := make([]*ui.Text, len(gameTypes))
 	for i, gameType := range gameTypes {
 		iCopy := i
 		gameTypeCopy := gameType
 
+		colors := &ui.ButtonColors
+		if !gameType.supportsParty(client.partyPlayers) {
+			colors = &ui.DisabledButtonColors
+		}
+
 		gameButtons[i] = ui.NewButton(ui.ButtonConfig{
 			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
-				return ui.CenteredPosition{X: width / 2, Y: 100 + height/3 + 100*iCopy}
+				return ui.CenteredPosition{X: width / 2, Y: 100 + height/3 + 120*iCopy}
 			}),
-			Text: gameType.DisplayName,
+			Text:   gameType.DisplayName + " (" + gameType.playerCountText() + ")",
+			Colors: colors,
 			Callback: func() {
-				startGame, err := json.Marshal(protocol.StartGamePacket{
-					PacketName: protocol.StartGamePacketName,
-					GameType:   gameTypeCopy.Name,
-				})
-				if err != nil {
-					panic(err)
+				if !gameTypeCopy.supportsParty(client.partyPlayers) {
+					return
 				}
-				client.SendPacket(startGame)
+
+				if len(gameTypeCopy.data.Options) != 0 {
+					client.currentScreen = newGameOptionsScreen(client, gameTypeCopy)
+					return
+				}
+
+				sendStartGame(client, gameTypeCopy, nil)
 			},
 		})
+
+		gameDescriptions[i] = ui.NewText(ui.TextConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: 150 + height/3 + 120*iCopy}
+			}),
+			Text: gameType.data.Description,
+		})
 	}
 
 	return &startGameScreen{
@@ -52,7 +68,8 @@ func newStartGameScreen(client *client) *startGameScreen {
 			Colors: &ui.TitleColors,
 			Font:   rescources.RobotoTitleFont,
 		}),
-		gameButtons: gameButtons,
+		gameButtons:      gameButtons,
+		gameDescriptions: gameDescriptions,
 	}
 }
 
@@ -63,6 +80,10 @@ func (s *startGameScreen) components() []ui.Component {
 		components = append(components, button)
 	}
 
+	for _, description := range s.gameDescriptions {
+		components = append(components, description)
+	}
+
 	return components
 }
 
@@ -12,7 +12,9 @@ type titleScreen struct {
 	title             *ui.Text
 	createPartyButton *ui.Button
 	joinPartyButton   *ui.Button
+	quickPlayButton   *ui.Button
 	changeNameButton  *ui.Button
+	replaysButton     *ui.Button
 }
 
 var _ screen = (*titleScreen)(nil)
@@ -46,20 +48,39 @@ func newTitleScreen(client *client) *titleScreen {
 				client.currentScreen = newJoinPartyScreenLoading(client)
 			},
 		}),
-		changeNameButton: ui.NewButton(ui.ButtonConfig{
+		quickPlayButton: ui.NewButton(ui.ButtonConfig{
 			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
 				return ui.CenteredPosition{X: width / 2, Y: (height/3)*2 + 200}
 			}),
+			Text: "Schnelles Spiel",
+			Callback: func() {
+				client.currentScreen = newQuickPlayScreen(client)
+			},
+		}),
+		changeNameButton: ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: (height/3)*2 + 300}
+			}),
 			Text: "Namen ändern",
 			Callback: func() {
 				client.currentScreen = newChangeNameScreen(client)
 			},
 		}),
+		replaysButton: ui.NewButton(ui.ButtonConfig{
+			Pos: ui.DynamicPosition(func(width, height int) ui.Position {
+				return ui.CenteredPosition{X: width / 2, Y: (height/3)*2 + 400}
+			}),
+			Text: "Wiederholungen",
+			Callback: func() {
+				client.currentScreen = newReplayListScreen(client)
+			},
+		}),
 	}
 }
 
 func (t *titleScreen) components() []ui.Component {
-	return []ui.Component{t.title, t.createPartyButton, t.joinPartyButton, t.changeNameButton}
+	return []ui.Component{t.title, t.createPartyButton, t.joinPartyButton, t.quickPlayButton, t.changeNameButton,
+		t.replaysButton}
 }
 
 func (t *titleScreen) update() {
@@ -68,7 +89,11 @@ func (t *titleScreen) update() {
 	} else if inpututil.IsKeyJustReleased(ebiten.Key2) {
 		t.client.currentScreen = newJoinPartyScreenLoading(t.client)
 	} else if inpututil.IsKeyJustReleased(ebiten.Key3) {
+		t.client.currentScreen = newQuickPlayScreen(t.client)
+	} else if inpututil.IsKeyJustReleased(ebiten.Key4) {
 		t.client.currentScreen = newChangeNameScreen(t.client)
+	} else if inpututil.IsKeyJustReleased(ebiten.Key5) {
+		t.client.currentScreen = newReplayListScreen(t.client)
 	}
 
 	for _, component := range t.components() {
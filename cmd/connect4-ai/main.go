@@ -0,0 +1,145 @@
+// connect4-ai lässt die KI von Vier Gewinnt ohne Server gegen sich selbst spielen, um die Schwierigkeitsstufen zu
+// vergleichen und die Geschwindigkeit der Suche zu messen. Mit -position wird stattdessen eine Stellung in der
+// Notation von Vier Gewinnt analysiert und der beste Zug ausgegeben.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	shared "github.com/Lama06/Oinky-Party/connect4"
+	"github.com/Lama06/Oinky-Party/server/connect4/ai"
+)
+
+type totals struct {
+	wins     int
+	moves    int
+	nodes    int
+	duration time.Duration
+}
+
+func main() {
+	var (
+		rules       shared.Rules
+		red, yellow string
+		games       int
+		seed        int64
+		notation    string
+	)
+	flag.IntVar(&rules.Width, "width", 7, "Die Anzahl der Spalten")
+	flag.IntVar(&rules.Height, "height", 6, "Die Anzahl der Reihen")
+	flag.IntVar(&rules.WinningLength, "length", 4, "Wie viele Steine in einer Reihe liegen müssen, um zu gewinnen")
+	flag.StringVar(&red, "red", "mittel", "Die Schwierigkeitsstufe von Rot: leicht, mittel, schwer oder meister")
+	flag.StringVar(&yellow, "yellow", "schwer", "Die Schwierigkeitsstufe von Gelb: leicht, mittel, schwer oder meister")
+	flag.IntVar(&games, "games", 10, "Wie viele Spiele gespielt werden")
+	flag.Int64Var(&seed, "seed", 0, "Der Seed für den Zufall. 0 bedeutet, dass ein zufälliger Seed gewählt wird.")
+	flag.StringVar(&notation, "position", "", "Eine Stellung in Notation (z.B. 4453), die mit der Stufe von -red analysiert wird")
+	flag.Parse()
+
+	rules.Players = 2
+	if !rules.Valid() || !ai.Supports(rules) {
+		log.Fatalf("invalid rules: %+v", rules)
+	}
+	redDifficulty, ok := ai.DifficultyByName(red)
+	if !ok {
+		log.Fatalf("unknown difficulty: %s", red)
+	}
+	yellowDifficulty, ok := ai.DifficultyByName(yellow)
+	if !ok {
+		log.Fatalf("unknown difficulty: %s", yellow)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	fmt.Printf("seed: %d\n", seed)
+
+	random := rand.New(rand.NewSource(seed))
+	if notation != "" {
+		analyze(rules, notation, redDifficulty, random)
+		return
+	}
+
+	searchers := map[shared.Color]*ai.Searcher{
+		shared.RedColor:    ai.NewSearcher(redDifficulty, rand.New(rand.NewSource(random.Int63()))),
+		shared.YellowColor: ai.NewSearcher(yellowDifficulty, rand.New(rand.NewSource(random.Int63()))),
+	}
+	results := map[shared.Color]*totals{
+		shared.RedColor:    {},
+		shared.YellowColor: {},
+	}
+	draws := 0
+
+	for i := 1; i <= games; i++ {
+		position := ai.NewPosition(rules)
+		for {
+			color := position.ToMove()
+			column, stats, ok := searchers[color].ChooseColumn(position, nil)
+			if !ok {
+				break
+			}
+			position.Play(column)
+
+			results[color].moves++
+			results[color].nodes += stats.Nodes
+			results[color].duration += stats.Duration
+		}
+
+		winner, draw, _ := position.Result()
+		switch {
+		case draw:
+			draws++
+			fmt.Printf("game %d: draw after %d moves\n", i, position.Moves())
+		case winner == shared.RedColor:
+			results[shared.RedColor].wins++
+			fmt.Printf("game %d: red (%s) won after %d moves\n", i, red, position.Moves())
+		default:
+			results[shared.YellowColor].wins++
+			fmt.Printf("game %d: yellow (%s) won after %d moves\n", i, yellow, position.Moves())
+		}
+	}
+
+	fmt.Printf("draws: %d\n", draws)
+	for _, color := range []shared.Color{shared.RedColor, shared.YellowColor} {
+		name, result := red, results[color]
+		if color == shared.YellowColor {
+			name = yellow
+		}
+		if result.moves == 0 {
+			continue
+		}
+		fmt.Printf(
+			"%s: %d wins, %d nodes and %v per move\n",
+			name, result.wins, result.nodes/result.moves, result.duration/time.Duration(result.moves),
+		)
+	}
+}
+
+// analyze gibt den Zug aus, den die KI in der Stellung wählt.
+func analyze(rules shared.Rules, notation string, difficulty ai.Difficulty, random *rand.Rand) {
+	position, err := ai.NewPositionFromNotation(rules, notation)
+	if err != nil {
+		log.Fatalf("invalid position: %v", err)
+	}
+
+	if winner, draw, over := position.Result(); over {
+		if draw {
+			fmt.Println("the game is a draw")
+		} else if winner == shared.RedColor {
+			fmt.Println("the game is over, red won")
+		} else {
+			fmt.Println("the game is over, yellow won")
+		}
+		return
+	}
+
+	column, stats, ok := ai.NewSearcher(difficulty, random).ChooseColumn(position, nil)
+	if !ok {
+		log.Fatal("no move found")
+	}
+	best := shared.FormatMove(shared.Move{Player: position.ToMove(), X: column})
+	fmt.Printf("best move: %s (depth %d, %d nodes, %v)\n", best, stats.Depth, stats.Nodes, stats.Duration)
+	fmt.Printf("position: %s%s\n", notation, best)
+}
@@ -1,49 +1,147 @@
 package connect4
 
+import "github.com/Lama06/Oinky-Party/protocol"
+
 const (
-	Name = "connect4"
+	Name    = "connect4"
+	Version = 3
 
-	BoardWidth  = 7
-	BoardHeight = 6
+	MinPlayers = 2
+	MaxPlayers = 4
 )
 
-type Color bool
+// Einstellungen
 
 const (
-	RedColor    Color = true
-	YellowColor Color = false
+	BoardWidthOption    = "board-width"
+	BoardHeightOption   = "board-height"
+	WinningLengthOption = "winning-length"
+	PopOutOption        = "pop-out"
+	DifficultyOption    = "ai-difficulty"
 )
 
-func (c Color) ToCell() Cell {
-	switch c {
-	case RedColor:
-		return RedCell
-	case YellowColor:
-		return YellowCell
-	default:
-		panic("unreachable")
+// Die Schwierigkeitsstufen der Bots
+const (
+	EasyDifficulty int32 = iota
+	MediumDifficulty
+	HardDifficulty
+	MasterDifficulty
+)
+
+var Options = protocol.Options{
+	{
+		Name:        BoardWidthOption,
+		DisplayName: "Spalten",
+		Type:        protocol.IntOption,
+		Min:         4,
+		Max:         12,
+		Default:     7,
+	},
+	{
+		Name:        BoardHeightOption,
+		DisplayName: "Reihen",
+		Type:        protocol.IntOption,
+		Min:         4,
+		Max:         10,
+		Default:     6,
+	},
+	{
+		Name:        WinningLengthOption,
+		DisplayName: "Steine in einer Reihe",
+		Type:        protocol.IntOption,
+		Min:         3,
+		Max:         6,
+		Default:     4,
+	},
+	{
+		Name:        PopOutOption,
+		DisplayName: "Pop Out (Rechtsklick)",
+		Type:        protocol.BoolOption,
+		Default:     0,
+	},
+	{
+		Name:        DifficultyOption,
+		DisplayName: "Stärke der Bots",
+		Type:        protocol.EnumOption,
+		Choices:     []string{"Leicht", "Mittel", "Schwer", "Meister"},
+		Default:     MediumDifficulty,
+	},
+}
+
+// Difficulty gibt die Schwierigkeitsstufe der Bots zurück.
+func Difficulty(options protocol.OptionValues) int32 {
+	return options.Int(DifficultyOption)
+}
+
+// Rules sind die Regeln, die beim Starten des Spieles gewählt wurden.
+type Rules struct {
+	Width, Height int
+	WinningLength int  // Wie viele Steine in einer Reihe liegen müssen, um zu gewinnen
+	Players       int  // Die Anzahl der Farben
+	PopOut        bool // Ob die Spieler eigene Steine aus der untersten Reihe entfernen dürfen
+}
+
+// RulesFromOptions liest die Regeln aus den Einstellungen. Die Anzahl der Spieler wird mit WithPlayers festgelegt.
+func RulesFromOptions(options protocol.OptionValues) Rules {
+	return Rules{
+		Width:         int(options.Int(BoardWidthOption)),
+		Height:        int(options.Int(BoardHeightOption)),
+		WinningLength: int(options.Int(WinningLengthOption)),
+		Players:       MinPlayers,
+		PopOut:        options.Bool(PopOutOption),
 	}
 }
 
-type Cell byte
+// minBoardSizes ist die Mindestgröße des Spielfeldes für mehr als zwei Spieler, damit alle genug Platz haben.
+var minBoardSizes = map[int]struct{ width, height int }{
+	3: {width: 9, height: 7},
+	4: {width: 10, height: 8},
+}
+
+// WithPlayers legt die Anzahl der Spieler fest und vergrößert das Spielfeld, falls es für sie zu klein ist.
+func (r Rules) WithPlayers(players int) Rules {
+	r.Players = players
+	if size, ok := minBoardSizes[players]; ok {
+		if r.Width < size.width {
+			r.Width = size.width
+		}
+		if r.Height < size.height {
+			r.Height = size.height
+		}
+	}
+	return r
+}
+
+// Valid gibt an, ob mit diesen Regeln überhaupt eine Reihe gebildet werden kann.
+func (r Rules) Valid() bool {
+	return r.Width > 0 && r.Height > 0 && r.WinningLength > 0 &&
+		(r.WinningLength <= r.Width || r.WinningLength <= r.Height) &&
+		r.Players >= MinPlayers && r.Players <= MaxPlayers
+}
+
+// Color ist die Farbe eines Spielers. Sie entspricht seinem Platz in der Reihenfolge der Züge.
+type Color byte
 
 const (
-	EmptyCell Cell = iota
-	RedCell
-	YellowCell
+	RedColor Color = iota
+	YellowColor
+	GreenColor
+	BlueColor
 )
 
+func (c Color) ToCell() Cell {
+	return Cell(c + 1)
+}
+
+type Cell byte
+
+const EmptyCell Cell = 0
+
 func (c Cell) ToColor() Color {
-	switch c {
-	case EmptyCell:
+	if c == EmptyCell {
 		panic("cannot convert empty cell to color")
-	case RedCell:
-		return RedColor
-	case YellowCell:
-		return YellowColor
-	default:
-		panic("unreachable")
 	}
+	return Color(c - 1)
 }
 
 // Client zu Server
@@ -55,8 +153,26 @@ type PlacePacket struct {
 	X          int32
 }
 
+const PopPacketName = "connect-4-player-pop"
+
+// PopPacket entfernt bei Pop Out einen eigenen Stein aus der untersten Reihe der Spalte.
+type PopPacket struct {
+	PacketName string
+	X          int32
+}
+
 // Server zu Client
 
+const StartPacketName = "connect-4-start"
+
+// StartPacket wird zu Beginn des Spieles gesendet und teilt mit, welcher Spieler welche Farbe hat und wie groß das
+// Spielfeld ist. Rot beginnt.
+type StartPacket struct {
+	PacketName string
+	Players    []int32 // Die IDs der Spieler, der Index ist ihre Farbe
+	Rules      Rules
+}
+
 const PlayerPlacedPacketName = "connect-4-player-placed"
 
 type PlayerPlacedPacket struct {
@@ -64,3 +180,47 @@ type PlayerPlacedPacket struct {
 	Player     Color
 	X          int32
 }
+
+const PlayerPoppedPacketName = "connect-4-player-popped"
+
+// PlayerPoppedPacket teilt mit, dass ein Stein aus der untersten Reihe entfernt wurde. Die Steine darüber rutschen
+// nach unten.
+type PlayerPoppedPacket struct {
+	PacketName string
+	Player     Color
+	X          int32
+}
+
+const PlayerEliminatedPacketName = "connect-4-player-eliminated"
+
+// PlayerEliminatedPacket wird gesendet, wenn ein Spieler bei mehr als zwei Spielern aufgegeben oder das Spiel
+// verlassen hat. Seine Steine bleiben liegen, die anderen spielen weiter.
+type PlayerEliminatedPacket struct {
+	PacketName string
+	Player     Color
+}
+
+type Position struct {
+	X, Y int
+}
+
+// ResultDetails ist der Inhalt von protocol.GameResultData.Details.
+// Entstehen bei Pop Out Reihen für mehrere Spieler, gewinnt der Spieler, der den Stein entfernt hat. Hat er selbst
+// keine Reihe, gewinnen die anderen Spieler gemeinsam.
+type ResultDetails struct {
+	Draw     bool
+	Winners  []Color
+	Cells    []Position // Die Steine, mit denen die Gewinner gewonnen haben
+	Moves    []Move     // Alle Züge des Spieles in ihrer Reihenfolge
+	Notation string     // Die Züge in der Notation von FormatNotation
+}
+
+// Snapshot ist der Inhalt des protocol.GameSnapshotPacket.
+type Snapshot struct {
+	Rules      Rules
+	Board      [][]Cell // Board[x][y], die Reihe mit y = 0 ist oben
+	Players    []int32
+	Eliminated []Color
+	Moves      []Move
+	Turn       protocol.TurnChangedPacket
+}
@@ -0,0 +1,74 @@
+package connect4
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Move ist ein Zug, den ein Spieler gemacht hat.
+type Move struct {
+	Player Color
+	X      int
+	Pop    bool // Ob der unterste Stein der Spalte bei Pop Out entfernt wurde
+}
+
+// Die Notation beschreibt ein Spiel als Folge der Spalten, in die gesetzt wurde. Die Spalten werden ab 1 gezählt,
+// nach der 9 folgen a, b und c. Entfernt ein Spieler bei Pop Out einen Stein, steht vor der Spalte ein "-".
+// "4453" bedeutet also, dass zweimal in die vierte Spalte, dann in die fünfte und dann in die dritte gesetzt wurde.
+// Wer einen Zug gemacht hat, steht nicht in der Notation, sondern ergibt sich aus der Reihenfolge der Züge.
+// Der Client zeigt die Notation nach dem Ende des Spieles an und speichert sie mit S in einer Datei. Eine Stellung ohne
+// Pop Out kann mit "connect4-ai -position <Notation>" analysiert werden.
+
+const (
+	notationColumns = "123456789abc"
+	notationPop     = '-'
+)
+
+// FormatNotation gibt die Notation der Züge zurück.
+func FormatNotation(moves []Move) string {
+	var notation strings.Builder
+	for _, move := range moves {
+		if move.Pop {
+			notation.WriteRune(notationPop)
+		}
+		if move.X < 0 || move.X >= len(notationColumns) {
+			notation.WriteRune('?')
+			continue
+		}
+		notation.WriteByte(notationColumns[move.X])
+	}
+	return notation.String()
+}
+
+// FormatMove gibt die Notation eines einzelnen Zuges zurück.
+func FormatMove(move Move) string {
+	return FormatNotation([]Move{move})
+}
+
+// ParseNotation liest die Züge aus der Notation. Da die Notation die Spieler nicht enthält, ist Move.Player nicht
+// gesetzt. Ob die Züge gültig sind, muss beim Nachspielen geprüft werden.
+func ParseNotation(notation string) ([]Move, error) {
+	var moves []Move
+	pop := false
+	for _, r := range strings.ToLower(strings.TrimSpace(notation)) {
+		if r == notationPop {
+			if pop {
+				return nil, errors.New("duplicate pop marker")
+			}
+			pop = true
+			continue
+		}
+
+		x := strings.IndexRune(notationColumns, r)
+		if x == -1 {
+			return nil, fmt.Errorf("invalid column: %q", r)
+		}
+		moves = append(moves, Move{X: x, Pop: pop})
+		pop = false
+	}
+	if pop {
+		return nil, errors.New("missing column after pop marker")
+	}
+	return moves, nil
+}
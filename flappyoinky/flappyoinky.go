@@ -1,5 +1,7 @@
 package flappyoinky
 
+import "github.com/Lama06/Oinky-Party/protocol"
+
 // Die X und Y Koordinaten der Oinkys und Hindernisse sind vom Typ float64 und liegen im Bereich 0 bis 1.
 // Der Punkt (0, 0) liegt in der oberen linken Ecke des Bildschirmes.
 // Die Koordinaten von den Oinkys und Hindernissen geben Auskunft über die Position der oberen linken Ecke der jeweiligen Objekte.
@@ -11,7 +13,8 @@ package flappyoinky
 // Wenn ein Spieler gestorben ist, erkennt der Client das daran, dass dieser Spieler nicht mehr im UpdatePacket zu finden ist.
 
 const (
-	Name = "flappyoinky"
+	Name    = "flappyoinky"
+	Version = 1
 
 	OinkySize            = 0.06              // Die Höhe und Breite des Oinkys
 	OinkyPosX            = 0.5 - OinkySize/2 // Die permanente X Position der oberen linken Ecke des Oinkys
@@ -19,12 +22,59 @@ const (
 	OinkyAccelerationY   = 0.001             // Der Wert, mit dem die Geschwindigkeit der Oinkys jede Sekunde erhöht wird
 	OinkySpeedYAfterJump = -0.02             // Der Wert der Geschwindigkeit der Oinkys nach einem Sprung
 
-	ObstacleSpawnRate       = 70     // Der Abstand in Ticks, in dem Hindernisse spawnen
-	ObstacleWidth           = 0.06   // Die Breite der Hindernisse
-	ObstacleFreeSpaceHeight = 0.4    // Die Höhe des freien Platzes der Hindernisse
-	ObstacleSpeed           = -0.005 // Die Geschwindigkeit, mit der die X Koordinate der Hindernisse pro Tick erhöht wird
+	ObstacleWidth = 0.06 // Die Breite der Hindernisse
 )
 
+// Einstellungen
+
+const (
+	ObstacleSpawnRateOption       = "obstacle-spawn-rate"
+	ObstacleFreeSpaceHeightOption = "obstacle-free-space-height"
+	ObstacleSpeedOption           = "obstacle-speed"
+)
+
+var Options = protocol.Options{
+	{
+		Name:        ObstacleSpawnRateOption,
+		DisplayName: "Abstand der Hindernisse",
+		Type:        protocol.IntOption,
+		Min:         40,
+		Max:         120,
+		Default:     70,
+	},
+	{
+		Name:        ObstacleFreeSpaceHeightOption,
+		DisplayName: "Lücke in Prozent",
+		Type:        protocol.IntOption,
+		Min:         25,
+		Max:         60,
+		Default:     40,
+	},
+	{
+		Name:        ObstacleSpeedOption,
+		DisplayName: "Geschwindigkeit",
+		Type:        protocol.IntOption,
+		Min:         1,
+		Max:         10,
+		Default:     5,
+	},
+}
+
+// ObstacleSpawnRate gibt den Abstand in Ticks zurück, in dem Hindernisse spawnen.
+func ObstacleSpawnRate(options protocol.OptionValues) int {
+	return int(options.Int(ObstacleSpawnRateOption))
+}
+
+// ObstacleFreeSpaceHeight gibt die Höhe des freien Platzes der Hindernisse zurück.
+func ObstacleFreeSpaceHeight(options protocol.OptionValues) float64 {
+	return float64(options.Int(ObstacleFreeSpaceHeightOption)) / 100
+}
+
+// ObstacleSpeed gibt die Geschwindigkeit zurück, mit der die X Koordinate der Hindernisse pro Tick erhöht wird.
+func ObstacleSpeed(options protocol.OptionValues) float64 {
+	return -float64(options.Int(ObstacleSpeedOption)) / 1000
+}
+
 // Client zu Server
 
 const JumpPacketName = "oinky-bird-jump"
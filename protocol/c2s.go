@@ -1,7 +1,17 @@
 package protocol
 
+const ResumeSessionPacketName = "resume-session"
+
+// ResumeSessionPacket setzt nach einem Verbindungsabbruch die alte Sitzung fort. Der Client erhält seine alte ID und
+// seinen Namen zurück und tritt wieder seiner Party bei, falls es sie noch gibt.
+type ResumeSessionPacket struct {
+	PacketName   string
+	SessionToken string
+}
+
 const ChangeNamePacketName = "change-name"
 
+// Der Server antwortet auf das ChangeNamePacket mit einem NameChangedPacket oder einem NameRejectedPacket.
 type ChangeNamePacket struct {
 	PacketName string
 	NewName    string
@@ -20,6 +30,22 @@ type QueryPartiesPacket struct {
 	PacketName string
 }
 
+// Nach dem SubscribePartiesPacket erhält der Client ein ListPartiesPacket und danach bei jeder Änderung
+// ein PartyCreatedPacket, PartyUpdatedPacket oder PartyRemovedPacket, bis er das UnsubscribePartiesPacket sendet
+// oder einer Party beitritt.
+
+const SubscribePartiesPacketName = "subscribe-parties"
+
+type SubscribePartiesPacket struct {
+	PacketName string
+}
+
+const UnsubscribePartiesPacketName = "unsubscribe-parties"
+
+type UnsubscribePartiesPacket struct {
+	PacketName string
+}
+
 const JoinPartyPacketName = "join-party"
 
 type JoinPartyPacket struct {
@@ -38,6 +64,7 @@ const StartGamePacketName = "start-game"
 type StartGamePacket struct {
 	PacketName string
 	GameType   string
+	Options    OptionValues // Fehlende Einstellungen erhalten ihren Standardwert
 }
 
 const EndGamePacketName = "end-game"
@@ -45,3 +72,91 @@ const EndGamePacketName = "end-game"
 type EndGamePacket struct {
 	PacketName string
 }
+
+const PauseGamePacketName = "pause-game"
+
+// PauseGamePacket pausiert das laufende Spiel oder setzt es fort. Der Host kann das sofort tun. Die anderen Spieler,
+// die am Spiel teilnehmen, stimmen damit ab. Das Spiel wird pausiert oder fortgesetzt, sobald mehr als die Hälfte von
+// ihnen dafür gestimmt hat.
+type PauseGamePacket struct {
+	PacketName string
+	Paused     bool
+}
+
+const AddPlaylistEntryPacketName = "add-playlist-entry"
+
+type AddPlaylistEntryPacket struct {
+	PacketName string
+	GameType   string
+	Rounds     int32
+	Options    OptionValues
+}
+
+const RemovePlaylistEntryPacketName = "remove-playlist-entry"
+
+type RemovePlaylistEntryPacket struct {
+	PacketName string
+	Index      int32
+}
+
+const StartPlaylistPacketName = "start-playlist"
+
+type StartPlaylistPacket struct {
+	PacketName string
+}
+
+const StopPlaylistPacketName = "stop-playlist"
+
+type StopPlaylistPacket struct {
+	PacketName string
+}
+
+const FindMatchPacketName = "find-match"
+
+type FindMatchPacket struct {
+	PacketName string
+	GameType   string
+}
+
+const CancelFindMatchPacketName = "cancel-find-match"
+
+type CancelFindMatchPacket struct {
+	PacketName string
+}
+
+const SetTeamPacketName = "set-team"
+
+type SetTeamPacket struct {
+	PacketName string
+	Player     int32
+	Team       int32
+}
+
+const BalanceTeamsPacketName = "balance-teams"
+
+// BalanceTeamsPacket verteilt die Spieler der Party zufällig und gleichmäßig auf die angegebene Anzahl an Teams.
+type BalanceTeamsPacket struct {
+	PacketName string
+	Teams      int32
+}
+
+const AddBotPacketName = "add-bot"
+
+// AddBotPacket fügt der Party einen Bot hinzu, der vom Server gesteuert wird. Nur der Host kann Bots hinzufügen.
+type AddBotPacket struct {
+	PacketName string
+}
+
+const RemoveBotPacketName = "remove-bot"
+
+type RemoveBotPacket struct {
+	PacketName string
+	Id         int32
+}
+
+const RematchPacketName = "rematch"
+
+// RematchPacket stimmt dafür, das letzte Spiel mit denselben Spielern und Einstellungen noch einmal zu spielen.
+type RematchPacket struct {
+	PacketName string
+}
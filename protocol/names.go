@@ -0,0 +1,58 @@
+package protocol
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinNameLength = 1
+	MaxNameLength = 20 // In Zeichen, nicht in Bytes
+)
+
+// NameRejectionReason gibt an, warum der Server einen Namen abgelehnt hat.
+type NameRejectionReason string
+
+const (
+	NameTooShort          NameRejectionReason = "too-short"
+	NameTooLong           NameRejectionReason = "too-long"
+	NameInvalidCharacters NameRejectionReason = "invalid-characters"
+	NameChangeInParty     NameRejectionReason = "in-party" // Der Name kann nicht geändert werden, während man in einer Party ist
+)
+
+// Neben Buchstaben, Ziffern und Leerzeichen sind diese Zeichen in Namen erlaubt.
+const allowedNameSymbols = "-_.,!?'()"
+
+// NormalizeName entfernt Leerzeichen am Anfang und Ende des Namens und fasst mehrere Leerzeichen zusammen.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func isAllowedNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == ' ' ||
+		strings.ContainsRune(allowedNameSymbols, r)
+}
+
+// ValidateName prüft einen Namen, der bereits mit NormalizeName normalisiert wurde.
+func ValidateName(name string) (reason NameRejectionReason, ok bool) {
+	if !utf8.ValidString(name) {
+		return NameInvalidCharacters, false
+	}
+
+	length := utf8.RuneCountInString(name)
+	if length < MinNameLength {
+		return NameTooShort, false
+	}
+	if length > MaxNameLength {
+		return NameTooLong, false
+	}
+
+	for _, r := range name {
+		if !isAllowedNameRune(r) {
+			return NameInvalidCharacters, false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,140 @@
+package protocol
+
+import "fmt"
+
+// Spiele können Einstellungen anbieten, die beim Starten des Spieles gewählt werden.
+// Alle Werte werden als int32 übertragen: Bei Wahrheitswerten steht 0 für false und 1 für true,
+// bei Auswahlmöglichkeiten ist der Wert der Index der gewählten Möglichkeit.
+
+type OptionType byte
+
+const (
+	IntOption OptionType = iota
+	BoolOption
+	EnumOption
+)
+
+type Option struct {
+	Name        string // Der eindeutige Schlüssel der Einstellung
+	DisplayName string
+	Type        OptionType
+	Min, Max    int32    // Der erlaubte Bereich bei IntOption
+	Choices     []string // Die Anzeigenamen der Auswahlmöglichkeiten bei EnumOption
+	Default     int32
+}
+
+func (o Option) valid(value int32) bool {
+	switch o.Type {
+	case IntOption:
+		return value >= o.Min && value <= o.Max
+	case BoolOption:
+		return value == 0 || value == 1
+	case EnumOption:
+		return value >= 0 && int(value) < len(o.Choices)
+	default:
+		return false
+	}
+}
+
+// Next gibt den Wert zurück, der auf value folgt. Nach dem höchsten Wert folgt wieder der niedrigste.
+func (o Option) Next(value int32) int32 {
+	switch o.Type {
+	case IntOption:
+		if value >= o.Max {
+			return o.Min
+		}
+		return value + 1
+	case BoolOption:
+		return 1 - value
+	case EnumOption:
+		return (value + 1) % int32(len(o.Choices))
+	default:
+		return value
+	}
+}
+
+// Previous gibt den Wert zurück, der vor value liegt. Vor dem niedrigsten Wert liegt wieder der höchste.
+func (o Option) Previous(value int32) int32 {
+	switch o.Type {
+	case IntOption:
+		if value <= o.Min {
+			return o.Max
+		}
+		return value - 1
+	case BoolOption:
+		return 1 - value
+	case EnumOption:
+		return (value - 1 + int32(len(o.Choices))) % int32(len(o.Choices))
+	default:
+		return value
+	}
+}
+
+// Format gibt den Anzeigenamen des Wertes zurück.
+func (o Option) Format(value int32) string {
+	switch o.Type {
+	case BoolOption:
+		if value != 0 {
+			return "Ja"
+		}
+		return "Nein"
+	case EnumOption:
+		if value >= 0 && int(value) < len(o.Choices) {
+			return o.Choices[value]
+		}
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+type Options []Option
+
+func (o Options) Defaults() OptionValues {
+	values := make(OptionValues, len(o))
+	for _, option := range o {
+		values[option.Name] = option.Default
+	}
+	return values
+}
+
+// Validate prüft die gewählten Werte und ergänzt fehlende Werte mit ihrem Standardwert.
+func (o Options) Validate(values OptionValues) (OptionValues, error) {
+	result := o.Defaults()
+
+	for name, value := range values {
+		option, ok := o.byName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown option: %s", name)
+		}
+
+		if !option.valid(value) {
+			return nil, fmt.Errorf("invalid value for option %s: %d", name, value)
+		}
+
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+func (o Options) byName(name string) (Option, bool) {
+	for _, option := range o {
+		if option.Name == name {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+type OptionValues map[string]int32
+
+func (v OptionValues) Int(name string) int32 {
+	value, ok := v[name]
+	if !ok {
+		panic("missing option: " + name)
+	}
+	return value
+}
+
+func (v OptionValues) Bool(name string) bool {
+	return v.Int(name) != 0
+}
@@ -6,8 +6,9 @@ import (
 )
 
 const (
-	Port      = 3333
-	TickSpeed = 50
+	Port         = 3333
+	TickSpeed    = 50
+	MaxPartySize = 8
 )
 
 type NamedPacket struct {
@@ -23,15 +24,77 @@ func GetPacketName(data []byte) (string, error) {
 	return named.PacketName, nil
 }
 
+const MaxTeams = 4
+
+// NoTeam ist das Team von Spielern, die keinem Team zugeordnet sind.
+const NoTeam = 0
+
 type PlayerData struct {
 	Name string
 	Id   int32
+	Team int32 // Eine Zahl zwischen 1 und MaxTeams oder NoTeam
+	Bot  bool  // Ob der Spieler vom Server gesteuert wird
 }
 
 type PartyData struct {
-	Name    string
-	Id      int32
-	Players []PlayerData
+	Name        string
+	Id          int32
+	Host        int32
+	Players     []PlayerData
+	Playlist    PlaylistData
+	GameRunning bool
+	GameType    string // Der Name des laufenden Spieles oder leer, wenn kein Spiel läuft
+	Spectatable bool   // Ob man der Party während des laufenden Spieles als Zuschauer beitreten kann
+}
+
+func (p PartyData) Full() bool {
+	return len(p.Players) >= MaxPartySize
+}
+
+// Joinable gibt an, ob der Party beigetreten werden kann.
+func (p PartyData) Joinable() bool {
+	return !p.Full() && (!p.GameRunning || p.Spectatable)
+}
+
+// GameTypeData beschreibt ein Spiel, das auf dem Server verfügbar ist.
+type GameTypeData struct {
+	Name               string
+	Description        string
+	MinPlayers         int32
+	MaxPlayers         int32 // 0 bedeutet, dass es keine Obergrenze gibt
+	SupportsSpectators bool
+	SupportsBots       bool
+	SoloOpponent       bool  // Ob in einer Party mit nur einem Spieler automatisch ein Bot als Gegner hinzugefügt wird
+	TickRate           int32 // Wie oft der Server pro Sekunde Updates sendet. 0 bei rundenbasierten Spielen.
+	Version            int32 // Client und Server können ein Spiel nur zusammen spielen, wenn die Versionen übereinstimmen
+	Options            Options
+}
+
+type PlaylistEntryData struct {
+	GameType string
+	Rounds   int32
+	Options  OptionValues
+}
+
+type PlaylistData struct {
+	Entries []PlaylistEntryData
+	Running bool
+}
+
+// GameResultData beschreibt den Ausgang eines Spieles.
+// Ranking enthält die IDs der Spieler nach ihrer Platzierung geordnet. Der erste Eintrag enthält die Gewinner.
+// Spieler, die sich eine Platzierung teilen, stehen im selben Eintrag.
+// Wurde das Spiel abgebrochen, ist Ranking leer.
+// Wurde das Spiel in Teams gespielt, enthält TeamRanking die Teams nach ihrer Platzierung geordnet.
+type GameResultData struct {
+	Ranking     [][]int32
+	TeamRanking [][]int32
+	Details     json.RawMessage `json:",omitempty"` // Spielspezifische Informationen über das Ergebnis
+}
+
+type StandingData struct {
+	Player PlayerData
+	Points int32
 }
 
 func Int32ToBytes(n int32) [4]byte {
@@ -0,0 +1,31 @@
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Der Server kann Spiele aufzeichnen und als JSON Datei speichern. Aufgezeichnet werden alle Packets, die das Spiel
+// an die ganze Party sendet. Packets an einzelne Spieler sind nicht enthalten, daher sieht man bei der Wiedergabe
+// das Spiel aus der Sicht eines Zuschauers.
+
+const ReplayFileExtension = ".replay.json"
+
+type ReplayPacket struct {
+	Time int64 // Die Zeit in Millisekunden seit dem Start des Spieles, ohne die Zeit, in der es pausiert war
+	Data json.RawMessage
+}
+
+type Replay struct {
+	GameType     string
+	GameVersion  int32
+	Options      OptionValues
+	Seed         int64 // Der Seed des Zufallsgenerators, mit dem das Spiel gespielt wurde
+	PartyName    string
+	Players      []PlayerData // Die Spieler der Party zu Beginn des Spieles
+	Participants []int32      // Die IDs der Spieler, die am Spiel teilgenommen haben
+	StartedAt    time.Time
+	Duration     int64 // In Millisekunden, ohne die Pausen
+	Packets      []ReplayPacket
+	Result       GameResultData
+}
@@ -1,11 +1,40 @@
 package protocol
 
+import "encoding/json"
+
 const WelcomePacketName = "welcome"
 
+// WelcomePacket wird nach dem Verbindungsaufbau und nach dem Fortsetzen einer Sitzung gesendet.
 type WelcomePacket struct {
+	PacketName   string
+	YourId       int32
+	YourName     string
+	SessionToken string // Geheim. Damit kann der Client seine Sitzung nach einem Verbindungsabbruch fortsetzen.
+}
+
+const NameChangedPacketName = "name-changed"
+
+// NameChangedPacket enthält den Namen, den der Server übernommen hat. Er kann vom gewünschten Namen abweichen,
+// zum Beispiel weil Leerzeichen entfernt wurden oder weil beim Beitritt zu einer Party ein Spieler mit demselben
+// Namen in der Party war.
+type NameChangedPacket struct {
+	PacketName string
+	Name       string
+}
+
+const NameRejectedPacketName = "name-rejected"
+
+type NameRejectedPacket struct {
 	PacketName string
-	YourId     int32
-	YourName   string
+	Reason     NameRejectionReason
+}
+
+const AvailableGamesPacketName = "available-games"
+
+// AvailableGamesPacket wird direkt nach dem WelcomePacket gesendet.
+type AvailableGamesPacket struct {
+	PacketName string
+	Games      []GameTypeData
 }
 
 const ListPartiesPacketName = "list-parties"
@@ -15,6 +44,27 @@ type ListPartiesPacket struct {
 	Parties    []PartyData
 }
 
+const PartyCreatedPacketName = "party-created"
+
+type PartyCreatedPacket struct {
+	PacketName string
+	Party      PartyData
+}
+
+const PartyUpdatedPacketName = "party-updated"
+
+type PartyUpdatedPacket struct {
+	PacketName string
+	Party      PartyData
+}
+
+const PartyRemovedPacketName = "party-removed"
+
+type PartyRemovedPacket struct {
+	PacketName string
+	Id         int32
+}
+
 const YouJoinedPartyPacketName = "you-joined-party"
 
 type YouJoinedPartyPacket struct {
@@ -47,10 +97,85 @@ const GameStartedPacketName = "game-started"
 type GameStartedPacket struct {
 	PacketName string
 	GameType   string
+	Players    []int32 // Die IDs der Spieler, die am Spiel teilnehmen. Alle anderen Spieler der Party schauen zu.
+	Options    OptionValues
+}
+
+const GameSnapshotPacketName = "game-snapshot"
+
+// GameSnapshotPacket wird nach dem GameStartedPacket an Spieler gesendet, die während eines laufenden Spieles
+// dazukommen. Es enthält den aktuellen Zustand des Spieles, soweit ihn der Spieler sehen darf.
+// Der Inhalt von Snapshot hängt vom Spiel ab.
+type GameSnapshotPacket struct {
+	PacketName string
+	Snapshot   json.RawMessage
 }
 
 const GameEndedPacketName = "game-ended"
 
 type GameEndedPacket struct {
 	PacketName string
+	Result     GameResultData
+	Seed       int64 // Der Seed des Zufallsgenerators, mit dem das Spiel gespielt wurde
+}
+
+const PauseStatusPacketName = "pause-status"
+
+// PauseStatusPacket wird gesendet, wenn das Spiel pausiert oder fortgesetzt wird oder sich die Abstimmung darüber ändert.
+type PauseStatusPacket struct {
+	PacketName  string
+	Paused      bool
+	PausedBy    int32   // Der Spieler, der das Spiel pausiert hat, oder 0, wenn es durch eine Abstimmung pausiert wurde
+	Votes       []int32 // Die Spieler, die dafür gestimmt haben, den aktuellen Zustand zu ändern
+	VotesNeeded int32
+}
+
+const TeamsChangedPacketName = "teams-changed"
+
+type TeamsChangedPacket struct {
+	PacketName string
+	Teams      map[int32]int32 // Ordnet jedem Spieler der Party sein Team zu
+}
+
+const HostChangedPacketName = "host-changed"
+
+type HostChangedPacket struct {
+	PacketName string
+	Id         int32
+}
+
+const PlaylistChangedPacketName = "playlist-changed"
+
+type PlaylistChangedPacket struct {
+	PacketName string
+	Playlist   PlaylistData
+}
+
+const StandingsPacketName = "standings"
+
+type StandingsPacket struct {
+	PacketName string
+	Standings  []StandingData
+}
+
+const RematchStatusPacketName = "rematch-status"
+
+// RematchStatusPacket wird gesendet, wenn sich die Abstimmung über eine Revanche ändert. Sobald alle Spieler des
+// letzten Spieles zugestimmt haben, wird es mit denselben Einstellungen erneut gestartet.
+type RematchStatusPacket struct {
+	PacketName string
+	Available  bool // false, wenn keine Revanche mehr möglich ist, zum Beispiel weil ein Spieler die Party verlassen hat
+	GameType   string
+	Players    []int32 // Die Spieler des letzten Spieles
+	Votes      []int32 // Die Spieler, die bereits zugestimmt haben. Bots stimmen immer zu.
+}
+
+const MatchmakingStatusPacketName = "matchmaking-status"
+
+// MatchmakingStatusPacket wird an alle wartenden Spieler gesendet, wenn sich die Warteschlange ändert.
+type MatchmakingStatusPacket struct {
+	PacketName string
+	GameType   string
+	Position   int32 // Die Position des Spielers in der Warteschlange, beginnend bei 1
+	QueueSize  int32
 }
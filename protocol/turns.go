@@ -0,0 +1,74 @@
+package protocol
+
+import "time"
+
+// Rundenbasierte Spiele senden ein TurnChangedPacket, wenn ein anderer Spieler an der Reihe ist.
+// Sie können die Bedenkzeit der Spieler mit den TurnOptions begrenzen.
+
+const (
+	TurnTimeOption      = "turn-time"
+	TotalTimeOption     = "total-time"
+	TimeoutActionOption = "timeout-action"
+)
+
+type TimeoutAction int32
+
+const (
+	SkipTurnTimeoutAction   TimeoutAction = iota // Der nächste Spieler ist an der Reihe
+	RandomMoveTimeoutAction                      // Für den Spieler wird ein zufälliger Zug gemacht
+	ForfeitTimeoutAction                         // Der Spieler verliert das Spiel
+)
+
+var turnTimes = []time.Duration{0, 10 * time.Second, 30 * time.Second, time.Minute}
+
+var totalTimes = []time.Duration{0, time.Minute, 3 * time.Minute, 5 * time.Minute, 10 * time.Minute}
+
+var TurnOptions = Options{
+	{
+		Name:        TurnTimeOption,
+		DisplayName: "Zeit pro Zug",
+		Type:        EnumOption,
+		Choices:     []string{"Unbegrenzt", "10 Sekunden", "30 Sekunden", "1 Minute"},
+		Default:     0,
+	},
+	{
+		Name:        TotalTimeOption,
+		DisplayName: "Bedenkzeit",
+		Type:        EnumOption,
+		Choices:     []string{"Unbegrenzt", "1 Minute", "3 Minuten", "5 Minuten", "10 Minuten"},
+		Default:     0,
+	},
+	{
+		Name:        TimeoutActionOption,
+		DisplayName: "Wenn die Zeit abläuft",
+		Type:        EnumOption,
+		Choices:     []string{"Aussetzen", "Zufälliger Zug", "Aufgeben"},
+		Default:     int32(RandomMoveTimeoutAction),
+	},
+}
+
+// TurnTime gibt zurück, wie lange ein Spieler für einen Zug Zeit hat. 0 bedeutet, dass es keine Begrenzung gibt.
+func TurnTime(options OptionValues) time.Duration {
+	return turnTimes[options.Int(TurnTimeOption)]
+}
+
+// TotalTime gibt zurück, wie viel Zeit ein Spieler für alle Züge zusammen hat. 0 bedeutet, dass es keine Begrenzung
+// gibt. Wenn sie abgelaufen ist, verliert der Spieler das Spiel.
+func TotalTime(options OptionValues) time.Duration {
+	return totalTimes[options.Int(TotalTimeOption)]
+}
+
+func GetTimeoutAction(options OptionValues) TimeoutAction {
+	return TimeoutAction(options.Int(TimeoutActionOption))
+}
+
+const TurnChangedPacketName = "turn-changed"
+
+// TurnChangedPacket wird gesendet, wenn ein Spieler an die Reihe kommt.
+// Alle Zeiten werden in Millisekunden angegeben.
+type TurnChangedPacket struct {
+	PacketName    string
+	Player        int32
+	TurnTimeLeft  int32           // -1, wenn die Zeit pro Zug nicht begrenzt ist
+	TotalTimeLeft map[int32]int32 // Die verbleibende Bedenkzeit jedes Spielers oder nil, wenn sie nicht begrenzt ist
+}
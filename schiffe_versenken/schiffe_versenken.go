@@ -1,32 +1,260 @@
 package schiffe_versenken
 
-import "sort"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+)
+
+const (
+	Name    = "schiffe_versenken"
+	Version = 4
+)
+
+// Einstellungen
 
 const (
-	Name        = "schiffe_versenken"
-	BoardWidth  = 10
-	BoardHeight = 10
+	BoardWidthOption  = "board-width"
+	BoardHeightOption = "board-height"
+	FleetOption       = "fleet"
+	DifficultyOption  = "ai-difficulty"
+	SetupTimeOption   = "setup-time"
 )
 
-var NumberOfShips = map[int]int{
-	1: 1,
-	2: 2,
+// Die Schwierigkeitsstufen der Bots
+const (
+	EasyDifficulty int32 = iota
+	MediumDifficulty
+	HardDifficulty
+)
+
+// Fleet gibt für jede Länge an, wie viele Schiffe dieser Länge aufgestellt werden.
+type Fleet map[int]int
+
+// Fleets sind die Flotten, die in den Einstellungen gewählt werden können. Der Index entspricht dem Wert der
+// Einstellung FleetOption.
+var Fleets = []Fleet{
+	{5: 1, 4: 1, 3: 2, 2: 1}, // Klassisch
+	{5: 1, 4: 2, 3: 3, 2: 4}, // Deutsch
+	{4: 1, 3: 2, 2: 3, 1: 4}, // Traditionell
+	{2: 2, 1: 1},             // Schnell
+}
+
+// CustomFleet ist der Wert der Einstellung FleetOption, bei dem die Anzahl der Schiffe jeder Länge mit den
+// Einstellungen ShipCountOption gewählt wird. Er folgt auf die Flotten aus Fleets.
+const CustomFleet = 4
+
+// MaxShipLength ist die Länge der längsten Schiffe, die es in einer eigenen Flotte geben kann.
+const MaxShipLength = 5
+
+// ShipCountOption gibt den Namen der Einstellung zurück, mit der die Anzahl der Schiffe mit der Länge in einer
+// eigenen Flotte gewählt wird.
+func ShipCountOption(length int) string {
+	return fmt.Sprintf("ships-%d", length)
+}
+
+// shipCountOption gibt die Einstellung für die Anzahl der Schiffe mit der Länge zurück. Als Standard wird die
+// klassische Flotte verwendet.
+func shipCountOption(length int) protocol.Option {
+	return protocol.Option{
+		Name:        ShipCountOption(length),
+		DisplayName: fmt.Sprintf("Eigene Flotte: Länge %d", length),
+		Type:        protocol.IntOption,
+		Min:         0,
+		Max:         5,
+		Default:     int32(Fleets[0][length]),
+	}
+}
+
+var setupTimes = []time.Duration{0, time.Minute, 2 * time.Minute, 5 * time.Minute}
+
+var Options = protocol.Options{
+	{
+		Name:        BoardWidthOption,
+		DisplayName: "Spalten",
+		Type:        protocol.IntOption,
+		Min:         6,
+		Max:         15,
+		Default:     10,
+	},
+	{
+		Name:        BoardHeightOption,
+		DisplayName: "Reihen",
+		Type:        protocol.IntOption,
+		Min:         6,
+		Max:         12,
+		Default:     10,
+	},
+	{
+		Name:        FleetOption,
+		DisplayName: "Flotte",
+		Type:        protocol.EnumOption,
+		Choices:     []string{"Klassisch", "Deutsch", "Traditionell", "Schnell", "Eigene"},
+		Default:     0,
+	},
+	shipCountOption(5),
+	shipCountOption(4),
+	shipCountOption(3),
+	shipCountOption(2),
+	shipCountOption(1),
+	{
+		Name:        DifficultyOption,
+		DisplayName: "Stärke der Bots",
+		Type:        protocol.EnumOption,
+		Choices:     []string{"Leicht", "Mittel", "Schwer"},
+		Default:     MediumDifficulty,
+	},
+	{
+		Name:        SetupTimeOption,
+		DisplayName: "Zeit zum Aufstellen",
+		Type:        protocol.EnumOption,
+		Choices:     []string{"Unbegrenzt", "1 Minute", "2 Minuten", "5 Minuten"},
+		Default:     0,
+	},
+}
+
+// Difficulty gibt die Schwierigkeitsstufe der Bots zurück.
+func Difficulty(options protocol.OptionValues) int32 {
+	return options.Int(DifficultyOption)
+}
+
+// SetupTime gibt zurück, wie lange die Spieler Zeit haben, ihre Schiffe aufzustellen. 0 bedeutet, dass es keine
+// Begrenzung gibt. Danach werden die Schiffe der Spieler, die noch nicht fertig sind, zufällig aufgestellt.
+func SetupTime(options protocol.OptionValues) time.Duration {
+	return setupTimes[options.Int(SetupTimeOption)]
+}
+
+// Rules sind die Regeln, die beim Starten des Spieles gewählt wurden.
+type Rules struct {
+	Width, Height int
+	Fleet         Fleet
+}
+
+// RulesFromOptions liest die Regeln aus den Einstellungen.
+func RulesFromOptions(options protocol.OptionValues) Rules {
+	rules := Rules{
+		Width:  int(options.Int(BoardWidthOption)),
+		Height: int(options.Int(BoardHeightOption)),
+	}
+	switch fleet := int(options.Int(FleetOption)); {
+	case fleet == CustomFleet:
+		rules.Fleet = Fleet{}
+		for length := 1; length <= MaxShipLength; length++ {
+			if count := int(options.Int(ShipCountOption(length))); count != 0 {
+				rules.Fleet[length] = count
+			}
+		}
+	case fleet >= 0 && fleet < len(Fleets):
+		rules.Fleet = Fleets[fleet]
+	}
+	return rules
+}
+
+// Lengths gibt die Längen aller Schiffe der Flotte zurück, die längsten zuerst.
+func (f Fleet) Lengths() []int {
+	var lengths []int
+	for length, count := range f {
+		for i := 0; i < count; i++ {
+			lengths = append(lengths, length)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+	return lengths
+}
+
+// Check prüft, ob die Flotte auf das Spielfeld passt. Jedes Schiff belegt zusammen mit dem Abstand zu den anderen
+// Schiffen ungefähr (Länge + 1) * 2 Felder. Damit die Schiffe noch zufällig aufgestellt werden können, dürfen sie so
+// höchstens drei Viertel des Spielfeldes belegen. Außerdem muss FirstFitShips die Flotte aufstellen können, damit
+// sicher ist, dass es überhaupt eine Aufstellung gibt.
+func (r Rules) Check() error {
+	if r.Width <= 0 || r.Height <= 0 {
+		return fmt.Errorf("invalid board size: %dx%d", r.Width, r.Height)
+	}
+
+	area := 0
+	for length, count := range r.Fleet {
+		if length <= 0 || count < 0 {
+			return fmt.Errorf("invalid number of ships with length %d: %d", length, count)
+		}
+		if length > r.Width && length > r.Height {
+			return fmt.Errorf("ships with length %d do not fit on a %dx%d board", length, r.Width, r.Height)
+		}
+		area += (length + 1) * 2 * count
+	}
+	if area == 0 {
+		return errors.New("the fleet is empty")
+	}
+	if area*4 > (r.Width+1)*(r.Height+1)*3 {
+		return fmt.Errorf("fleet does not fit on a %dx%d board", r.Width, r.Height)
+	}
+	if _, ok := r.FirstFitShips(); !ok {
+		return fmt.Errorf("fleet does not fit on a %dx%d board", r.Width, r.Height)
+	}
+	return nil
+}
+
+// FirstFitShips stellt die Flotte, die längsten Schiffe zuerst, jeweils auf das erste Feld, auf dem das Schiff Platz
+// hat. ok ist false, wenn dabei für ein Schiff kein Platz mehr bleibt.
+func (r Rules) FirstFitShips() (ships Ships, ok bool) {
+	blocked := make(map[Position]struct{})
+	for _, length := range r.Fleet.Lengths() {
+		ship, found := r.firstFit(length, blocked)
+		if !found {
+			return nil, false
+		}
+		ships = append(ships, ship)
+		for pos := range ship.BlockedFields(r) {
+			blocked[pos] = struct{}{}
+		}
+	}
+	return ships, true
+}
+
+func (r Rules) firstFit(length int, blocked map[Position]struct{}) (Ship, bool) {
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			for _, horizontal := range []bool{true, false} {
+				ship := NewShip(Position{X: x, Y: y}, length, horizontal)
+				if ship.Valid(r) && !ship.overlaps(blocked) {
+					return ship, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// Valid gibt an, ob Check keinen Fehler findet.
+func (r Rules) Valid() bool {
+	return r.Check() == nil
+}
+
+// Fields gibt zurück, aus wie vielen Feldern alle Schiffe zusammen bestehen.
+func (r Rules) Fields() int {
+	fields := 0
+	for length, count := range r.Fleet {
+		fields += length * count
+	}
+	return fields
 }
 
 type Position struct {
 	X, Y int
 }
 
-func (p Position) Valid() bool {
-	return p.X >= 0 && p.X < BoardWidth && p.Y >= 0 && p.Y < BoardHeight
+func (p Position) Valid(rules Rules) bool {
+	return p.X >= 0 && p.X < rules.Width && p.Y >= 0 && p.Y < rules.Height
 }
 
-func (p Position) Neighbours() map[Position]struct{} {
+func (p Position) Neighbours(rules Rules) map[Position]struct{} {
 	result := make(map[Position]struct{})
 	for _, xOffset := range []int{-1, 0, 1} {
 		for _, yOffset := range []int{-1, 0, 1} {
 			neighbour := Position{p.X + xOffset, p.Y + yOffset}
-			if neighbour.Valid() && neighbour != p {
+			if neighbour.Valid(rules) && neighbour != p {
 				result[neighbour] = struct{}{}
 			}
 		}
@@ -36,6 +264,19 @@ func (p Position) Neighbours() map[Position]struct{} {
 
 type Ship []Position
 
+// NewShip gibt das Schiff zurück, das bei start beginnt und nach rechts oder nach unten reicht.
+func NewShip(start Position, length int, horizontal bool) Ship {
+	ship := make(Ship, length)
+	for i := range ship {
+		if horizontal {
+			ship[i] = Position{X: start.X + i, Y: start.Y}
+		} else {
+			ship[i] = Position{X: start.X, Y: start.Y + i}
+		}
+	}
+	return ship
+}
+
 func areIntsEqual(ints []int) bool {
 	if len(ints) == 0 {
 		return true
@@ -70,9 +311,9 @@ func areIntsContiguous(ints []int) bool {
 	return true
 }
 
-func (s Ship) valid() bool {
+func (s Ship) Valid(rules Rules) bool {
 	for _, pos := range s {
-		if !pos.Valid() {
+		if !pos.Valid(rules) {
 			return false
 		}
 	}
@@ -91,11 +332,21 @@ func (s Ship) valid() bool {
 		(areIntsEqual(yPositions) && areIntsContiguous(xPositions))
 }
 
-func (s Ship) blockedFields() map[Position]struct{} {
+func (s Ship) overlaps(fields map[Position]struct{}) bool {
+	for _, pos := range s {
+		if _, ok := fields[pos]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedFields gibt die Felder des Schiffes und alle Felder daneben zurück. Andere Schiffe dürfen dort nicht liegen.
+func (s Ship) BlockedFields(rules Rules) map[Position]struct{} {
 	result := make(map[Position]struct{})
 	for _, pos := range s {
 		result[pos] = struct{}{}
-		for neighbour := range pos.Neighbours() {
+		for neighbour := range pos.Neighbours(rules) {
 			result[neighbour] = struct{}{}
 		}
 	}
@@ -104,7 +355,8 @@ func (s Ship) blockedFields() map[Position]struct{} {
 
 type Ships []Ship
 
-func (s Ships) countShipsWithLength(length int) (result int) {
+// CountShipsWithLength gibt zurück, wie viele Schiffe die Länge haben.
+func (s Ships) CountShipsWithLength(length int) (result int) {
 	for _, ship := range s {
 		if len(ship) == length {
 			result++
@@ -113,23 +365,25 @@ func (s Ships) countShipsWithLength(length int) (result int) {
 	return
 }
 
-func (s Ships) Valid() bool {
+// Valid gibt an, ob die Schiffe der Flotte aus den Regeln entsprechen, auf dem Spielfeld liegen und sich nicht
+// berühren.
+func (s Ships) Valid(rules Rules) bool {
 	for _, ship := range s {
-		if !ship.valid() {
+		if !ship.Valid(rules) {
 			return false
 		}
 	}
 
 	totalShipCountShould := 0
-	for _, count := range NumberOfShips {
+	for _, count := range rules.Fleet {
 		totalShipCountShould += count
 	}
 	if totalShipCountShould != len(s) {
 		return false
 	}
 
-	for shipLength, count := range NumberOfShips {
-		if s.countShipsWithLength(shipLength) != count {
+	for shipLength, count := range rules.Fleet {
+		if s.CountShipsWithLength(shipLength) != count {
 			return false
 		}
 	}
@@ -140,7 +394,7 @@ func (s Ships) Valid() bool {
 				continue
 			}
 
-			for shipBlockedField := range ship.blockedFields() {
+			for shipBlockedField := range ship.BlockedFields(rules) {
 				for _, otherShipField := range otherShip {
 					if shipBlockedField == otherShipField {
 						return false
@@ -173,6 +427,31 @@ type FirePacket struct {
 
 // Server zu Client
 
+// RulesPacketName wird beim Starten des Spieles gesendet, bevor die Spieler ihre Schiffe aufstellen.
+const RulesPacketName = packetNamePrefix + "rules"
+
+type RulesPacket struct {
+	PacketName string
+	Rules      Rules
+}
+
+const SetupTimePacketName = packetNamePrefix + "setup-time"
+
+// SetupTimePacket wird gesendet, wenn die Zeit zum Aufstellen der Schiffe begrenzt ist, und nach einer Pause erneut.
+type SetupTimePacket struct {
+	PacketName    string
+	SetupTimeLeft int32 // In Millisekunden
+}
+
+const ShipsPlacedPacketName = packetNamePrefix + "ships-placed"
+
+// ShipsPlacedPacket wird an einen Spieler gesendet, dessen Schiffe zufällig aufgestellt wurden, weil die Zeit zum
+// Aufstellen abgelaufen ist.
+type ShipsPlacedPacket struct {
+	PacketName string
+	Ships      Ships
+}
+
 const GameStartedPacketName = packetNamePrefix + "game-started"
 
 type GameStartedPacket struct {
@@ -187,6 +466,8 @@ type FireResultPacket struct {
 	PacketName string
 	Position   Position
 	Hit        bool
+	Sunk       bool // Ob das getroffene Schiff damit versenkt wurde
+	Ship       Ship // Alle Felder des versenkten Schiffes, nur gesetzt, wenn Sunk true ist
 }
 
 const OpponentFiredPacketName = packetNamePrefix + "ship-destroyed"
@@ -194,4 +475,29 @@ const OpponentFiredPacketName = packetNamePrefix + "ship-destroyed"
 type OpponentFiredPacket struct {
 	PacketName string
 	Position   Position
+	Hit        bool
+	Sunk       bool
+}
+
+// ResultDetails sind die Details des protocol.GameResultData. Am Ende des Spieles werden die Flotten beider Spieler
+// aufgedeckt.
+type ResultDetails struct {
+	Ships map[int32]Ships // Die Schiffe der Spieler nach ihrer ID, nil, wenn ein Spieler sie nicht aufgestellt hat
+}
+
+type Shot struct {
+	Position Position
+	Hit      bool
+}
+
+// Snapshot ist der Inhalt des protocol.GameSnapshotPacket. Er enthält nur die Informationen, die der Spieler sehen darf.
+type Snapshot struct {
+	Rules         Rules
+	Ships         Ships      // Die eigenen Schiffe oder nil, wenn sie noch nicht aufgestellt wurden
+	Shots         []Shot     // Die Schüsse des Spielers auf das Spielfeld des Gegners
+	SunkShips     Ships      // Die Schiffe des Gegners, die der Spieler versenkt hat
+	OpponentShots []Position // Die Schüsse des Gegners auf das eigene Spielfeld
+	Started       bool
+	SetupTimeLeft int32                      // In Millisekunden, -1, wenn die Zeit zum Aufstellen nicht begrenzt ist
+	Turn          protocol.TurnChangedPacket // Nur gültig, wenn Started true ist
 }
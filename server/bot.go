@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// bot steuert einen Spieler, der keine Verbindung zum Server hat.
+// Die Strategie, mit der er spielt, wird zu Beginn jedes Spieles vom game.Type erstellt.
+type bot struct {
+	strategy game.Bot // nil, wenn der Bot gerade an keinem Spiel teilnimmt
+	outgoing [][]byte // Die Packets, die der Bot im nächsten Tick an das Spiel sendet
+}
+
+func newBotPlayer(s *server, name string) *player {
+	return &player{
+		name:   name,
+		id:     s.playerIds.allocate(),
+		server: s,
+		bot:    &bot{},
+	}
+}
+
+func (b *bot) start(self *player, t game.Type, options protocol.OptionValues, random *rand.Rand) {
+	b.outgoing = nil
+	b.strategy = t.Bot(self, options, random, func(data []byte) {
+		b.outgoing = append(b.outgoing, data)
+	})
+}
+
+func (b *bot) stop() {
+	if stoppable, ok := b.strategy.(game.StoppableBot); ok {
+		stoppable.Stop()
+	}
+	b.strategy = nil
+	b.outgoing = nil
+}
+
+func (b *bot) handlePacket(self *player, data []byte) {
+	if b.strategy == nil {
+		return
+	}
+
+	err := b.strategy.HandlePacket(data)
+	if err != nil {
+		log.Println(fmt.Errorf("bot %s(%d) failed to handle packet: %w", self.name, self.id, err))
+	}
+}
+
+// tick lässt den Bot seinen nächsten Zug planen und gibt die Packets zurück, die er an das Spiel senden will.
+func (b *bot) tick() [][]byte {
+	if b.strategy == nil {
+		return nil
+	}
+
+	b.strategy.Tick()
+
+	outgoing := b.outgoing
+	b.outgoing = nil
+	return outgoing
+}
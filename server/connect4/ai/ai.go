@@ -0,0 +1,306 @@
+// Package ai enthält einen Computergegner für Vier Gewinnt, der mit Alpha-Beta-Suche und einer
+// Transpositionstabelle spielt. Er wird von den Bots des Servers und vom Kommandozeilenprogramm cmd/connect4-ai benutzt.
+package ai
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	shared "github.com/Lama06/Oinky-Party/connect4"
+)
+
+// Difficulty legt fest, wie stark die KI spielt.
+type Difficulty struct {
+	Name        string
+	Depth       int     // Wie viele Züge höchstens vorausberechnet werden
+	NodeBudget  int     // Wie viele Positionen höchstens für einen Zug bewertet werden
+	BlunderRate float64 // Die Wahrscheinlichkeit, mit der statt des besten Zuges ein zufälliger Zug gespielt wird
+}
+
+// Difficulties enthält die Schwierigkeitsstufen in der Reihenfolge der shared.DifficultyOption.
+var Difficulties = [...]Difficulty{
+	shared.EasyDifficulty: {
+		Name:        "leicht",
+		Depth:       2,
+		NodeBudget:  20_000,
+		BlunderRate: 0.25,
+	},
+	shared.MediumDifficulty: {
+		Name:        "mittel",
+		Depth:       4,
+		NodeBudget:  100_000,
+		BlunderRate: 0.1,
+	},
+	shared.HardDifficulty: {
+		Name:        "schwer",
+		Depth:       8,
+		NodeBudget:  500_000,
+		BlunderRate: 0.02,
+	},
+	shared.MasterDifficulty: {
+		Name:       "meister",
+		Depth:      100,
+		NodeBudget: 1_500_000,
+	},
+}
+
+func DifficultyByName(name string) (Difficulty, bool) {
+	for _, difficulty := range Difficulties {
+		if difficulty.Name == name {
+			return difficulty, true
+		}
+	}
+	return Difficulty{}, false
+}
+
+const (
+	winScore     = 1 << 24 // Ein Sieg ist mehr wert als jede Bewertung mit evaluate
+	maxTableSize = 1 << 20 // Danach wird die Transpositionstabelle geleert
+	cancelPeriod = 1024    // Nach so vielen Positionen wird geprüft, ob die Suche abgebrochen werden soll
+)
+
+type entryBound byte
+
+const (
+	exactBound entryBound = iota
+	lowerBound
+	upperBound
+)
+
+// entry ist ein Eintrag der Transpositionstabelle. Bei Siegen und Niederlagen wird im score gespeichert, wie viele
+// Züge sie von der Position entfernt sind, und nicht, wie weit sie von der Wurzel der Suche entfernt sind. So bleibt
+// der Eintrag gültig, wenn die Position in einer anderen Suche oder in einer anderen Tiefe wieder erreicht wird.
+type entry struct {
+	depth int
+	score int
+	bound entryBound
+	move  int
+}
+
+// Stats beschreibt, wie die KI einen Zug gefunden hat.
+type Stats struct {
+	Depth    int // Die Tiefe der letzten vollständigen Suche
+	Nodes    int
+	Duration time.Duration
+	Blunder  bool // Ob ein zufälliger Zug gespielt wurde
+}
+
+// Searcher sucht den besten Zug. Die Transpositionstabelle bleibt zwischen den Zügen erhalten.
+// Ein Searcher kann von mehreren Goroutinen benutzt werden, die Suchen laufen dann nacheinander.
+type Searcher struct {
+	mutex      sync.Mutex
+	difficulty Difficulty
+	random     *rand.Rand
+	table      map[uint64]entry
+
+	// Der Zustand der aktuellen Suche
+	nodes     int
+	aborted   bool
+	cancel    <-chan struct{}
+	cancelled bool
+}
+
+func NewSearcher(difficulty Difficulty, random *rand.Rand) *Searcher {
+	return &Searcher{
+		difficulty: difficulty,
+		random:     random,
+		table:      map[uint64]entry{},
+	}
+}
+
+// ChooseColumn gibt die Spalte zurück, in die die Farbe, die am Zug ist, setzen sollte. Ist das Spiel bereits vorbei,
+// wird ok auf false gesetzt. Die Position wird während der Suche verändert, aber wiederhergestellt.
+// Die Suche wird nach dem NodeBudget und nicht nach einer Zeit abgebrochen, damit der gewählte Zug nicht von der
+// Geschwindigkeit des Rechners abhängt und ein Spiel mit demselben Seed genauso wiederholt werden kann.
+// Wird cancel geschlossen, bricht die Suche ab und ok ist false. cancel kann nil sein.
+func (s *Searcher) ChooseColumn(position *Position, cancel <-chan struct{}) (column int, stats Stats, ok bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	start := time.Now()
+
+	columns := position.columns(-1)
+	if len(columns) == 0 {
+		return 0, Stats{}, false
+	}
+
+	if s.random.Float64() < s.difficulty.BlunderRate {
+		return columns[s.random.Intn(len(columns))], Stats{Blunder: true, Duration: time.Since(start)}, true
+	}
+
+	if len(s.table) > maxTableSize {
+		s.table = map[uint64]entry{}
+	}
+	s.nodes = 0
+	s.aborted = false
+	s.cancel = cancel
+	s.cancelled = false
+
+	maxDepth := s.difficulty.Depth
+	if remaining := position.rules.Width*position.rules.Height - position.Moves(); maxDepth > remaining {
+		maxDepth = remaining
+	}
+
+	// Iterative Vertiefung: Sind zu viele Positionen bewertet worden, wird der Zug der letzten vollständigen Suche gespielt
+	column = columns[0]
+	for depth := 1; depth <= maxDepth; depth++ {
+		best, score := s.searchRoot(position, depth)
+		if s.cancelled {
+			return 0, Stats{}, false
+		}
+		if s.aborted {
+			break
+		}
+
+		column = best
+		stats.Depth = depth
+		if score > winScore/2 || score < -winScore/2 {
+			break // Der Ausgang des Spieles steht bereits fest
+		}
+	}
+
+	stats.Nodes = s.nodes
+	stats.Duration = time.Since(start)
+	return column, stats, true
+}
+
+func (s *Searcher) searchRoot(position *Position, depth int) (column int, score int) {
+	alpha, beta := -winScore-1, winScore+1
+	column, score = -1, -winScore-1
+
+	previous := -1
+	if entry, ok := s.table[position.hash]; ok {
+		previous = entry.move
+	}
+
+	for _, x := range position.columns(previous) {
+		position.Play(x)
+		childScore := -s.negamax(position, depth-1, 1, -beta, -alpha, depth > 1)
+		position.undo()
+		if s.aborted {
+			return column, score
+		}
+
+		if childScore > score {
+			column, score = x, childScore
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+
+	s.table[position.hash] = entry{depth: depth, score: toTableScore(score, 0), bound: exactBound, move: column}
+	return column, score
+}
+
+// toTableScore wandelt die Bewertung einer Position, die ply Züge von der Wurzel entfernt ist, in die Bewertung für
+// die Transpositionstabelle um.
+func toTableScore(score, ply int) int {
+	switch {
+	case score > winScore/2:
+		return score + ply
+	case score < -winScore/2:
+		return score - ply
+	default:
+		return score
+	}
+}
+
+// fromTableScore ist die Umkehrung von toTableScore.
+func fromTableScore(score, ply int) int {
+	switch {
+	case score > winScore/2:
+		return score - ply
+	case score < -winScore/2:
+		return score + ply
+	default:
+		return score
+	}
+}
+
+// negamax bewertet die Position aus der Sicht der Farbe, die am Zug ist. Die Suche wird nur abgebrochen, wenn
+// abortable true ist, damit immer mindestens eine Suche vollständig ist.
+func (s *Searcher) negamax(position *Position, depth, ply, alpha, beta int, abortable bool) int {
+	s.nodes++
+	if abortable && s.difficulty.NodeBudget > 0 && s.nodes >= s.difficulty.NodeBudget {
+		s.aborted = true
+	}
+	if s.cancel != nil && s.nodes%cancelPeriod == 0 {
+		select {
+		case <-s.cancel:
+			s.aborted = true
+			s.cancelled = true
+		default:
+		}
+	}
+	if s.aborted {
+		return 0
+	}
+
+	if position.won {
+		// Der Gegner hat mit dem letzten Zug gewonnen. Frühe Niederlagen sind schlimmer als späte.
+		return -(winScore - ply)
+	}
+	if position.full() {
+		return 0
+	}
+	if depth == 0 {
+		return position.evaluate()
+	}
+
+	originalAlpha := alpha
+	previous := -1
+	if entry, ok := s.table[position.hash]; ok {
+		previous = entry.move
+		if entry.depth >= depth {
+			score := fromTableScore(entry.score, ply)
+			switch entry.bound {
+			case exactBound:
+				return score
+			case lowerBound:
+				if score > alpha {
+					alpha = score
+				}
+			case upperBound:
+				if score < beta {
+					beta = score
+				}
+			}
+			if alpha >= beta {
+				return score
+			}
+		}
+	}
+
+	best, bestMove := -winScore-1, -1
+	for _, x := range position.columns(previous) {
+		position.Play(x)
+		score := -s.negamax(position, depth-1, ply+1, -beta, -alpha, abortable)
+		position.undo()
+		if s.aborted {
+			return 0
+		}
+
+		if score > best {
+			best, bestMove = score, x
+		}
+		if best > alpha {
+			alpha = best
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	bound := exactBound
+	switch {
+	case best <= originalAlpha:
+		bound = upperBound
+	case best >= beta:
+		bound = lowerBound
+	}
+	s.table[position.hash] = entry{depth: depth, score: toTableScore(best, ply), bound: bound, move: bestMove}
+
+	return best
+}
@@ -0,0 +1,282 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	shared "github.com/Lama06/Oinky-Party/connect4"
+)
+
+// directions sind die Richtungen, in denen eine Reihe liegen kann: waagerecht, senkrecht und die beiden Diagonalen.
+var directions = [...]shared.Position{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: -1}}
+
+// tables enthält alles, was nur von den Regeln abhängt und deshalb von allen Kopien einer Position geteilt wird.
+type tables struct {
+	keys    [][][2]uint64       // Zufällige Zahlen für jede Zelle und Farbe, aus denen der Hash berechnet wird
+	windows [][]shared.Position // Alle Abschnitte, in denen eine Reihe entstehen kann
+	order   []int               // Die Spalten von der Mitte nach außen sortiert
+}
+
+func newTables(rules shared.Rules) *tables {
+	// Der Seed ist fest, damit dieselbe Position immer denselben Hash hat
+	random := rand.New(rand.NewSource(1))
+	keys := make([][][2]uint64, rules.Width)
+	for x := range keys {
+		keys[x] = make([][2]uint64, rules.Height)
+		for y := range keys[x] {
+			keys[x][y] = [2]uint64{random.Uint64(), random.Uint64()}
+		}
+	}
+
+	inside := func(pos shared.Position) bool {
+		return pos.X >= 0 && pos.X < rules.Width && pos.Y >= 0 && pos.Y < rules.Height
+	}
+	var windows [][]shared.Position
+	for x := 0; x < rules.Width; x++ {
+		for y := 0; y < rules.Height; y++ {
+			for _, direction := range directions {
+				end := shared.Position{
+					X: x + direction.X*(rules.WinningLength-1),
+					Y: y + direction.Y*(rules.WinningLength-1),
+				}
+				if !inside(end) {
+					continue
+				}
+
+				window := make([]shared.Position, rules.WinningLength)
+				for i := range window {
+					window[i] = shared.Position{X: x + direction.X*i, Y: y + direction.Y*i}
+				}
+				windows = append(windows, window)
+			}
+		}
+	}
+
+	order := make([]int, rules.Width)
+	for x := range order {
+		order[x] = x
+	}
+	center := float64(rules.Width-1) / 2
+	distance := func(x int) float64 {
+		if float64(x) < center {
+			return center - float64(x)
+		}
+		return float64(x) - center
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return distance(order[i]) < distance(order[j])
+	})
+
+	return &tables{
+		keys:    keys,
+		windows: windows,
+		order:   order,
+	}
+}
+
+// Supports gibt an, ob die KI mit diesen Regeln spielen kann. Sie unterstützt nur zwei Spieler ohne Pop Out.
+func Supports(rules shared.Rules) bool {
+	return rules.Players == 2 && !rules.PopOut
+}
+
+// opponent gibt die Farbe des anderen Spielers zurück.
+func opponent(color shared.Color) shared.Color {
+	if color == shared.RedColor {
+		return shared.YellowColor
+	}
+	return shared.RedColor
+}
+
+// Position ist ein Spielstand, auf dem die KI Züge ausprobieren und wieder zurücknehmen kann.
+// Rot beginnt, deshalb ergibt sich aus der Anzahl der Steine, wer am Zug ist.
+type Position struct {
+	rules   shared.Rules
+	tables  *tables
+	cells   [][]shared.Cell // cells[x][y], die Reihe mit y = 0 ist oben
+	heights []int           // Wie viele Steine in jeder Spalte liegen
+	moves   []int           // Die Spalten, in die bisher gesetzt wurde
+	hash    uint64
+	won     bool // Ob der letzte Zug eine Reihe vervollständigt hat
+}
+
+func NewPosition(rules shared.Rules) *Position {
+	cells := make([][]shared.Cell, rules.Width)
+	for x := range cells {
+		cells[x] = make([]shared.Cell, rules.Height)
+	}
+	return &Position{
+		rules:   rules,
+		tables:  newTables(rules),
+		cells:   cells,
+		heights: make([]int, rules.Width),
+	}
+}
+
+// NewPositionFromNotation spielt die Züge aus der Notation von shared.FormatNotation nach, um eine Stellung zu
+// analysieren. Da die KI kein Pop Out unterstützt, darf die Notation keine entfernten Steine enthalten.
+func NewPositionFromNotation(rules shared.Rules, notation string) (*Position, error) {
+	moves, err := shared.ParseNotation(notation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse notation: %w", err)
+	}
+
+	position := NewPosition(rules)
+	for i, move := range moves {
+		if move.Pop {
+			return nil, errors.New("pop moves are not supported")
+		}
+		if !position.Play(move.X) {
+			return nil, fmt.Errorf("illegal move %d in column: %d", i+1, move.X+1)
+		}
+	}
+	return position, nil
+}
+
+func (p *Position) Clone() *Position {
+	cells := make([][]shared.Cell, len(p.cells))
+	for x := range cells {
+		cells[x] = append([]shared.Cell(nil), p.cells[x]...)
+	}
+	return &Position{
+		rules:   p.rules,
+		tables:  p.tables,
+		cells:   cells,
+		heights: append([]int(nil), p.heights...),
+		moves:   append([]int(nil), p.moves...),
+		hash:    p.hash,
+		won:     p.won,
+	}
+}
+
+func (p *Position) Rules() shared.Rules {
+	return p.rules
+}
+
+// ToMove gibt die Farbe zurück, die als Nächstes setzt.
+func (p *Position) ToMove() shared.Color {
+	return shared.Color(len(p.moves) % 2)
+}
+
+// Moves gibt zurück, wie viele Steine bereits gesetzt wurden.
+func (p *Position) Moves() int {
+	return len(p.moves)
+}
+
+func (p *Position) CanPlay(x int) bool {
+	return !p.won && x >= 0 && x < p.rules.Width && p.heights[x] < p.rules.Height
+}
+
+// Play setzt einen Stein der Farbe, die am Zug ist, in die Spalte.
+func (p *Position) Play(x int) bool {
+	if !p.CanPlay(x) {
+		return false
+	}
+
+	color := p.ToMove()
+	y := p.rules.Height - 1 - p.heights[x]
+	p.cells[x][y] = color.ToCell()
+	p.hash ^= p.tables.keys[x][y][int(color)]
+	p.heights[x]++
+	p.moves = append(p.moves, x)
+	p.won = p.connects(shared.Position{X: x, Y: y})
+	return true
+}
+
+// undo nimmt den letzten Zug zurück.
+func (p *Position) undo() {
+	x := p.moves[len(p.moves)-1]
+	p.moves = p.moves[:len(p.moves)-1]
+	p.heights[x]--
+	y := p.rules.Height - 1 - p.heights[x]
+	color := p.cells[x][y].ToColor()
+	p.cells[x][y] = shared.EmptyCell
+	p.hash ^= p.tables.keys[x][y][int(color)]
+	p.won = false
+}
+
+func (p *Position) cell(pos shared.Position) shared.Cell {
+	if pos.X < 0 || pos.X >= p.rules.Width || pos.Y < 0 || pos.Y >= p.rules.Height {
+		return shared.EmptyCell
+	}
+	return p.cells[pos.X][pos.Y]
+}
+
+// connects gibt an, ob der Stein an pos Teil einer Reihe ist, die lang genug ist.
+func (p *Position) connects(pos shared.Position) bool {
+	cell := p.cell(pos)
+	for _, direction := range directions {
+		length := 1
+		for _, sign := range [...]int{1, -1} {
+			next := shared.Position{X: pos.X + sign*direction.X, Y: pos.Y + sign*direction.Y}
+			for p.cell(next) == cell {
+				length++
+				next = shared.Position{X: next.X + sign*direction.X, Y: next.Y + sign*direction.Y}
+			}
+		}
+		if length >= p.rules.WinningLength {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Position) full() bool {
+	return len(p.moves) == p.rules.Width*p.rules.Height
+}
+
+// Result gibt an, ob das Spiel vorbei ist und wer gewonnen hat.
+func (p *Position) Result() (winner shared.Color, draw bool, over bool) {
+	switch {
+	case p.won:
+		return opponent(p.ToMove()), false, true
+	case p.full():
+		return shared.RedColor, true, true
+	default:
+		return shared.RedColor, false, false
+	}
+}
+
+// columns gibt die Spalten, in die gesetzt werden kann, von der Mitte nach außen zurück. first wird zuerst
+// ausprobiert, falls es möglich ist.
+func (p *Position) columns(first int) []int {
+	columns := make([]int, 0, p.rules.Width)
+	if p.CanPlay(first) {
+		columns = append(columns, first)
+	}
+	for _, x := range p.tables.order {
+		if x != first && p.CanPlay(x) {
+			columns = append(columns, x)
+		}
+	}
+	return columns
+}
+
+// evaluate schätzt, wie gut die Position für die Farbe ist, die am Zug ist. Jeder Abschnitt, in dem nur Steine
+// einer Farbe liegen, zählt umso mehr, je voller er ist.
+func (p *Position) evaluate() int {
+	me := p.ToMove().ToCell()
+
+	score := 0
+	for _, window := range p.tables.windows {
+		mine, theirs := 0, 0
+		for _, pos := range window {
+			switch p.cells[pos.X][pos.Y] {
+			case shared.EmptyCell:
+			case me:
+				mine++
+			default:
+				theirs++
+			}
+		}
+
+		switch {
+		case theirs == 0 && mine != 0:
+			score += 1 << (2 * mine)
+		case mine == 0 && theirs != 0:
+			score -= 1 << (2 * theirs)
+		}
+	}
+	return score
+}
@@ -0,0 +1,257 @@
+package connect4
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	shared "github.com/Lama06/Oinky-Party/connect4"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/connect4/ai"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+const botMoveDelay = 10 // Wie viele Ticks der Bot mindestens wartet, bevor er einen Stein setzt
+
+type botMove struct {
+	x   int
+	pop bool
+}
+
+// bot spielt mit der KI aus dem Paket ai, wenn sie die Regeln unterstützt. Damit der Server nicht blockiert wird,
+// rechnet die KI in einer eigenen Goroutine mit einer Kopie der Position. Bei mehr als zwei Spielern oder Pop Out
+// gewinnt der Bot, wenn es möglich ist, verhindert sonst Reihen der Gegner und spielt ansonsten zufällig.
+type bot struct {
+	self           game.Player
+	send           func(data []byte)
+	board          *board       // nil, bis das shared.StartPacket empfangen wurde
+	position       *ai.Position // nil, wenn die KI die Regeln nicht unterstützt
+	searcher       *ai.Searcher
+	random         *rand.Rand
+	color          shared.Color
+	eliminated     map[shared.Color]bool
+	myTurn         bool
+	ticksUntilMove int
+	move           chan botMove  // Hier wird der gewählte Zug geliefert, nil, wenn keine Suche läuft
+	cancel         chan struct{} // Wird geschlossen, um die Suche der KI abzubrechen, nil, wenn keine Suche läuft
+}
+
+var _ game.StoppableBot = (*bot)(nil)
+
+func createBot(self game.Player, options protocol.OptionValues, random *rand.Rand, send func(data []byte)) game.Bot {
+	return &bot{
+		self:           self,
+		send:           send,
+		searcher:       ai.NewSearcher(ai.Difficulties[shared.Difficulty(options)], random),
+		random:         random,
+		eliminated:     map[shared.Color]bool{},
+		ticksUntilMove: botMoveDelay,
+	}
+}
+
+var _ game.BotCreator = createBot
+
+func (b *bot) HandlePacket(data []byte) error {
+	packetName, err := protocol.GetPacketName(data)
+	if err != nil {
+		return fmt.Errorf("failed to get packet name: %w", err)
+	}
+
+	switch packetName {
+	case shared.StartPacketName:
+		var start shared.StartPacket
+		err := json.Unmarshal(data, &start)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		for color, id := range start.Players {
+			if id == b.self.Id() {
+				b.color = shared.Color(color)
+			}
+		}
+		b.board = newBoard(start.Rules)
+		if ai.Supports(start.Rules) {
+			b.position = ai.NewPosition(start.Rules)
+		}
+	case protocol.TurnChangedPacketName:
+		var turnChanged protocol.TurnChangedPacket
+		err := json.Unmarshal(data, &turnChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		b.myTurn = turnChanged.Player == b.self.Id()
+		b.ticksUntilMove = botMoveDelay
+		b.stopSearch()
+		if b.myTurn {
+			b.startSearch()
+		}
+	case shared.PlayerPlacedPacketName:
+		var playerPlaced shared.PlayerPlacedPacket
+		err := json.Unmarshal(data, &playerPlaced)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if b.board == nil {
+			return errors.New("the game has not started yet")
+		}
+		b.board.place(playerPlaced.Player, int(playerPlaced.X))
+		if b.position != nil {
+			if playerPlaced.Player != b.position.ToMove() || !b.position.Play(int(playerPlaced.X)) {
+				return fmt.Errorf("unexpected move in column: %d", playerPlaced.X)
+			}
+		}
+	case shared.PlayerPoppedPacketName:
+		var playerPopped shared.PlayerPoppedPacket
+		err := json.Unmarshal(data, &playerPopped)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if b.board == nil {
+			return errors.New("the game has not started yet")
+		}
+		if !b.board.canPop(playerPopped.Player, int(playerPopped.X)) {
+			return fmt.Errorf("unexpected pop in column: %d", playerPopped.X)
+		}
+		b.board.pop(int(playerPopped.X))
+	case shared.PlayerEliminatedPacketName:
+		var playerEliminated shared.PlayerEliminatedPacket
+		err := json.Unmarshal(data, &playerEliminated)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		b.eliminated[playerEliminated.Player] = true
+	}
+
+	return nil
+}
+
+// startSearch sucht den nächsten Zug. Die KI rechnet dabei im Hintergrund. Welcher Zug gewählt wird, hängt nur vom
+// Zufallsgenerator des Bots ab und nicht davon, wie lange die Suche dauert.
+func (b *bot) startSearch() {
+	if b.board == nil {
+		return
+	}
+
+	move := make(chan botMove, 1)
+	b.move = move
+
+	if b.position == nil {
+		if chosen, ok := b.chooseMove(); ok {
+			move <- chosen
+		}
+		return
+	}
+
+	position := b.position.Clone()
+	cancel := make(chan struct{})
+	b.cancel = cancel
+	go func() {
+		x, _, ok := b.searcher.ChooseColumn(position, cancel)
+		if ok {
+			move <- botMove{x: x}
+		}
+	}()
+}
+
+// stopSearch bricht die laufende Suche ab. Ihr Ergebnis wird nicht mehr gebraucht.
+func (b *bot) stopSearch() {
+	b.move = nil
+	if b.cancel != nil {
+		close(b.cancel)
+		b.cancel = nil
+	}
+}
+
+func (b *bot) Stop() {
+	b.stopSearch()
+}
+
+// chooseMove gewinnt, wenn es möglich ist, und verhindert sonst, dass ein Gegner mit einem Stein in einer Spalte
+// gewinnen könnte. Ansonsten wird ein zufälliger Zug gewählt.
+func (b *bot) chooseMove() (move botMove, ok bool) {
+	var moves []botMove
+	for x := 0; x < b.board.rules.Width; x++ {
+		if b.board.canPlace(x) {
+			moves = append(moves, botMove{x: x})
+		}
+		if b.board.canPop(b.color, x) {
+			moves = append(moves, botMove{x: x, pop: true})
+		}
+	}
+	if len(moves) == 0 {
+		return botMove{}, false
+	}
+
+	for _, move := range moves {
+		next := b.board.clone()
+		if move.pop {
+			next.pop(move.x)
+		} else {
+			next.place(b.color, move.x)
+		}
+		if _, won := next.lines()[b.color]; won {
+			return move, true
+		}
+	}
+
+	for opponent := shared.Color(0); int(opponent) < b.board.rules.Players; opponent++ {
+		if opponent == b.color || b.eliminated[opponent] {
+			continue
+		}
+
+		for _, move := range moves {
+			if move.pop {
+				continue
+			}
+			next := b.board.clone()
+			next.place(opponent, move.x)
+			if _, won := next.lines()[opponent]; won {
+				return move, true
+			}
+		}
+	}
+
+	return moves[b.random.Intn(len(moves))], true
+}
+
+func (b *bot) Tick() {
+	if !b.myTurn || b.move == nil {
+		return
+	}
+
+	if b.ticksUntilMove > 0 {
+		b.ticksUntilMove--
+		return
+	}
+
+	var move botMove
+	select {
+	case move = <-b.move:
+		b.move = nil
+		b.cancel = nil
+	default:
+		return // Die KI rechnet noch
+	}
+
+	var packet any = shared.PlacePacket{
+		PacketName: shared.PlacePacketName,
+		X:          int32(move.x),
+	}
+	if move.pop {
+		packet = shared.PopPacket{
+			PacketName: shared.PopPacketName,
+			X:          int32(move.x),
+		}
+	}
+	data, err := json.Marshal(packet)
+	if err != nil {
+		panic(err)
+	}
+	b.send(data)
+}
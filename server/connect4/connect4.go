@@ -8,129 +8,289 @@ import (
 	shared "github.com/Lama06/Oinky-Party/connect4"
 	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/Lama06/Oinky-Party/server/game"
+	"github.com/Lama06/Oinky-Party/server/turns"
 )
 
-type board [shared.BoardWidth][shared.BoardHeight]shared.Cell
+// board ist das Spielfeld. Die Größe wird beim Starten des Spieles mit den Einstellungen festgelegt.
+type board struct {
+	rules shared.Rules
+	cells [][]shared.Cell // cells[x][y], die Reihe mit y = 0 ist oben
+}
 
-func getWinner(cells []shared.Cell) (winner shared.Color, found bool) {
-	var currentColor shared.Color
-	var hasCurrentColor bool
-	var currentCount int
-	for _, cell := range cells {
-		if cell == shared.EmptyCell {
-			hasCurrentColor = false
-			currentCount = 0
-			continue
-		}
-		if !hasCurrentColor || cell.ToColor() != currentColor {
-			currentColor = cell.ToColor()
-			hasCurrentColor = true
-			currentCount = 1
-			continue
-		}
-		currentCount++
-		if currentCount == 4 {
-			return currentColor, true
-		}
+func newBoard(rules shared.Rules) *board {
+	cells := make([][]shared.Cell, rules.Width)
+	for x := range cells {
+		cells[x] = make([]shared.Cell, rules.Height)
+	}
+	return &board{
+		rules: rules,
+		cells: cells,
 	}
-
-	return shared.RedColor, false
 }
 
-func (b *board) getWinnerInRow(y int) (winner shared.Color, found bool) {
-	cells := make([]shared.Cell, shared.BoardWidth)
-	for x := 0; x < shared.BoardWidth; x++ {
-		cells[x] = b[x][y]
+func (b *board) clone() *board {
+	clone := newBoard(b.rules)
+	for x := range b.cells {
+		copy(clone.cells[x], b.cells[x])
 	}
-	return getWinner(cells)
+	return clone
 }
 
-func (b *board) getWinnerInColumn(x int) (winner shared.Color, found bool) {
-	cells := make([]shared.Cell, shared.BoardHeight)
-	for y := 0; y < shared.BoardHeight; y++ {
-		cells[y] = b[x][y]
-	}
-	return getWinner(cells)
+// directions sind die Richtungen, in denen eine Reihe liegen kann: waagerecht, senkrecht und die beiden Diagonalen.
+var directions = [...]shared.Position{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: -1}}
+
+func (b *board) isInside(pos shared.Position) bool {
+	return pos.X >= 0 && pos.X < b.rules.Width && pos.Y >= 0 && pos.Y < b.rules.Height
 }
 
-func (b *board) getWinner() (winner shared.Color, found bool) {
-	for y := 0; y < shared.BoardHeight; y++ {
-		if winner, found := b.getWinnerInRow(y); found {
-			return winner, true
-		}
+func (b *board) cell(pos shared.Position) shared.Cell {
+	if !b.isInside(pos) {
+		return shared.EmptyCell
 	}
+	return b.cells[pos.X][pos.Y]
+}
+
+// lines sucht alle Reihen aus mindestens shared.Rules.WinningLength Steinen derselben Farbe und gibt für jede Farbe
+// die Steine ihrer Reihen zurück. Nach einem Pop Out können mehrere Farben gleichzeitig Reihen haben.
+func (b *board) lines() map[shared.Color][]shared.Position {
+	lines := map[shared.Color][]shared.Position{}
+	for x := 0; x < b.rules.Width; x++ {
+		for y := 0; y < b.rules.Height; y++ {
+			start := shared.Position{X: x, Y: y}
+			cell := b.cell(start)
+			if cell == shared.EmptyCell {
+				continue
+			}
+
+			for _, direction := range directions {
+				// Jede Reihe wird nur von ihrem ersten Stein aus gezählt
+				previous := shared.Position{X: x - direction.X, Y: y - direction.Y}
+				if b.cell(previous) == cell {
+					continue
+				}
 
-	for x := 0; x < shared.BoardHeight; x++ {
-		if winner, found := b.getWinnerInColumn(x); found {
-			return winner, true
+				var line []shared.Position
+				for pos := start; b.cell(pos) == cell; pos = (shared.Position{X: pos.X + direction.X, Y: pos.Y + direction.Y}) {
+					line = append(line, pos)
+				}
+
+				if len(line) >= b.rules.WinningLength {
+					lines[cell.ToColor()] = append(lines[cell.ToColor()], line...)
+				}
+			}
 		}
 	}
-
-	return shared.RedColor, false
+	return lines
 }
 
 func (b *board) canPlace(x int) bool {
-	return b[x][0] == shared.EmptyCell
+	return x >= 0 && x < b.rules.Width && b.cells[x][0] == shared.EmptyCell
 }
 
 func (b *board) place(color shared.Color, x int) {
-	y := shared.BoardHeight - 1
-	for b[x][y] != shared.EmptyCell {
+	if !b.canPlace(x) {
+		return
+	}
+
+	y := b.rules.Height - 1
+	for b.cells[x][y] != shared.EmptyCell {
 		y--
-		if y == -1 {
-			return
+	}
+	b.cells[x][y] = color.ToCell()
+}
+
+// canPop gibt an, ob die Farbe bei Pop Out den untersten Stein der Spalte entfernen darf.
+func (b *board) canPop(color shared.Color, x int) bool {
+	return b.rules.PopOut && x >= 0 && x < b.rules.Width && b.cells[x][b.rules.Height-1] == color.ToCell()
+}
+
+// pop entfernt den untersten Stein der Spalte. Die Steine darüber rutschen nach unten.
+func (b *board) pop(x int) {
+	column := b.cells[x]
+	copy(column[1:], column[:len(column)-1])
+	column[0] = shared.EmptyCell
+}
+
+// hasMove gibt an, ob die Farbe einen Stein setzen oder entfernen kann.
+func (b *board) hasMove(color shared.Color) bool {
+	for x := 0; x < b.rules.Width; x++ {
+		if b.canPlace(x) || b.canPop(color, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// key gibt eine Zeichenkette zurück, die die Stellung eindeutig beschreibt.
+func (b *board) key() string {
+	key := make([]byte, 0, b.rules.Width*b.rules.Height)
+	for _, column := range b.cells {
+		for _, cell := range column {
+			key = append(key, byte(cell))
 		}
 	}
-	b[x][y] = color.ToCell()
+	return string(key)
 }
 
+// maxRepetitions gibt an, wie oft sich eine Stellung bei Pop Out wiederholen darf, bevor das Spiel unentschieden
+// endet. Ohne diese Regel könnten die Spieler endlos Steine setzen und entfernen.
+const maxRepetitions = 3
+
 type impl struct {
-	party         game.Party
-	board         *board
-	red           game.Player
-	yellow        game.Player
-	currentPlayer shared.Color
+	party      game.Party
+	board      *board
+	players    []game.Player // Der Index ist die Farbe des Spielers
+	eliminated []game.Player // Die Spieler, die aufgegeben oder das Spiel verlassen haben, in dieser Reihenfolge
+	moves      []shared.Move
+	positions  map[string]int
+	turns      *turns.Order
 }
 
 var _ game.Game = (*impl)(nil)
+var _ game.Snapshotter = (*impl)(nil)
+var _ game.Pausable = (*impl)(nil)
+var _ turns.Handler = (*impl)(nil)
 
-func create(party game.Party) game.Game {
-	var players []game.Player
-	for _, player := range party.Players() {
-		players = append(players, player)
+func create(party game.Party, options protocol.OptionValues) game.Game {
+	// Der erste Spieler spielt mit Rot und beginnt
+	players := party.PlayerOrder()
+	if len(players) < shared.MinPlayers || len(players) > shared.MaxPlayers {
+		return nil
 	}
-	if len(players) != 2 {
+
+	rules := shared.RulesFromOptions(options).WithPlayers(len(players))
+	if !rules.Valid() {
 		return nil
 	}
 
-	return &impl{
-		party:         party,
-		board:         &board{},
-		red:           players[0],
-		yellow:        players[1],
-		currentPlayer: shared.RedColor,
+	i := &impl{
+		party:     party,
+		board:     newBoard(rules),
+		players:   players,
+		positions: map[string]int{},
 	}
+	i.turns = turns.NewOrder(party, i, turns.ConfigFromOptions(options), append([]game.Player(nil), players...))
+	return i
 }
 
 var _ game.Creator = create
 
 func (i *impl) getColor(player game.Player) shared.Color {
-	switch player {
-	case i.red:
-		return shared.RedColor
-	case i.yellow:
-		return shared.YellowColor
-	default:
-		panic("invalid player")
+	for color, p := range i.players {
+		if p == player {
+			return shared.Color(color)
+		}
 	}
+	panic("invalid player")
 }
 
-func (i *impl) HandleGameStarted() {}
+func (i *impl) isEliminated(player game.Player) bool {
+	for _, eliminated := range i.eliminated {
+		if eliminated == player {
+			return true
+		}
+	}
+	return false
+}
+
+// activePlayers gibt die Spieler zurück, die noch mitspielen.
+func (i *impl) activePlayers() []game.Player {
+	var active []game.Player
+	for _, player := range i.players {
+		if !i.isEliminated(player) {
+			active = append(active, player)
+		}
+	}
+	return active
+}
+
+// result platziert die Gewinner vor den übrigen Spielern. Ausgeschiedene Spieler landen auf den letzten Plätzen,
+// wer zuerst ausgeschieden ist, ganz hinten. Die Züge des Spieles werden immer zu den Details hinzugefügt.
+func (i *impl) result(winners []game.Player, details shared.ResultDetails) game.Result {
+	details.Moves = i.moves
+	details.Notation = shared.FormatNotation(i.moves)
+
+	var winnerIds, otherIds []int32
+	for _, player := range i.activePlayers() {
+		isWinner := false
+		for _, winner := range winners {
+			if winner == player {
+				isWinner = true
+			}
+		}
+
+		if isWinner {
+			winnerIds = append(winnerIds, player.Id())
+		} else {
+			otherIds = append(otherIds, player.Id())
+		}
+	}
+
+	ranking := [][]int32{winnerIds}
+	if len(otherIds) != 0 {
+		ranking = append(ranking, otherIds)
+	}
+	for j := len(i.eliminated) - 1; j >= 0; j-- {
+		ranking = append(ranking, []int32{i.eliminated[j].Id()})
+	}
+
+	return game.Result{
+		Ranking: ranking,
+		Details: details,
+	}
+}
+
+func (i *impl) HandleGameStarted() {
+	ids := make([]int32, len(i.players))
+	for color, player := range i.players {
+		ids[color] = player.Id()
+	}
+
+	start, err := json.Marshal(shared.StartPacket{
+		PacketName: shared.StartPacketName,
+		Players:    ids,
+		Rules:      i.board.rules,
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(start)
+
+	i.turns.Start()
+}
 
 func (i *impl) HandleGameEnded() {}
 
+// eliminate lässt einen Spieler ausscheiden. Bleibt nur ein Spieler übrig, gewinnt er.
+func (i *impl) eliminate(player game.Player) {
+	if i.isEliminated(player) {
+		return
+	}
+	i.eliminated = append(i.eliminated, player)
+	i.turns.Remove(player)
+
+	active := i.activePlayers()
+	if len(active) == 1 {
+		i.party.EndGame(i.result(active, shared.ResultDetails{}))
+		return
+	}
+
+	eliminated, err := json.Marshal(shared.PlayerEliminatedPacket{
+		PacketName: shared.PlayerEliminatedPacketName,
+		Player:     i.getColor(player),
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(eliminated)
+
+	if !i.board.hasMove(i.getColor(i.turns.Current())) {
+		i.nextTurn()
+	}
+}
+
 func (i *impl) HandlePlayerLeft(player game.Player) {
-	i.party.EndGame()
+	i.eliminate(player)
 }
 
 func (i *impl) HandlePacket(sender game.Player, data []byte) error {
@@ -140,48 +300,239 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 	}
 	switch packetName {
 	case shared.PlacePacketName:
-		var playerPlaced shared.PlayerPlacedPacket
-		err := json.Unmarshal(data, &playerPlaced)
+		var place shared.PlacePacket
+		err := json.Unmarshal(data, &place)
 		if err != nil {
 			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
 
-		if playerPlaced.X < 0 || playerPlaced.X > shared.BoardWidth-1 {
-			return fmt.Errorf("invalid column: %d", playerPlaced.X)
+		if place.X < 0 || int(place.X) > i.board.rules.Width-1 {
+			return fmt.Errorf("invalid column: %d", place.X)
 		}
-		if i.getColor(sender) != i.currentPlayer {
-			return errors.New("its not this players turn")
+		err = i.turns.CheckTurn(sender)
+		if err != nil {
+			return err
 		}
-		if !i.board.canPlace(int(playerPlaced.X)) {
-			return fmt.Errorf("cannot place in column: %d", playerPlaced.X)
+		if !i.board.canPlace(int(place.X)) {
+			return fmt.Errorf("cannot place in column: %d", place.X)
 		}
 
-		i.board.place(i.getColor(sender), int(playerPlaced.X))
-		i.currentPlayer = !i.currentPlayer
+		i.place(sender, int(place.X))
 
-		place, err := json.Marshal(shared.PlayerPlacedPacket{
-			PacketName: shared.PlayerPlacedPacketName,
-			Player:     i.getColor(sender),
-			X:          playerPlaced.X,
-		})
+		return nil
+	case shared.PopPacketName:
+		var pop shared.PopPacket
+		err := json.Unmarshal(data, &pop)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
-		i.party.BroadcastPacket(place)
 
-		if _, found := i.board.getWinner(); found {
-			i.party.EndGame()
+		if !i.board.rules.PopOut {
+			return errors.New("pop out is disabled")
+		}
+		if pop.X < 0 || int(pop.X) > i.board.rules.Width-1 {
+			return fmt.Errorf("invalid column: %d", pop.X)
+		}
+		err = i.turns.CheckTurn(sender)
+		if err != nil {
+			return err
+		}
+		if !i.board.canPop(i.getColor(sender), int(pop.X)) {
+			return fmt.Errorf("cannot pop in column: %d", pop.X)
 		}
 
+		i.pop(sender, int(pop.X))
+
 		return nil
 	default:
 		return errors.New("unknown packet name")
 	}
 }
 
-func (i *impl) Tick() {}
+func (i *impl) place(player game.Player, x int) {
+	i.board.place(i.getColor(player), x)
+	i.moves = append(i.moves, shared.Move{Player: i.getColor(player), X: x})
+
+	place, err := json.Marshal(shared.PlayerPlacedPacket{
+		PacketName: shared.PlayerPlacedPacketName,
+		Player:     i.getColor(player),
+		X:          int32(x),
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(place)
+
+	i.finishMove(player)
+}
+
+func (i *impl) pop(player game.Player, x int) {
+	i.board.pop(x)
+	i.moves = append(i.moves, shared.Move{Player: i.getColor(player), X: x, Pop: true})
+
+	popped, err := json.Marshal(shared.PlayerPoppedPacket{
+		PacketName: shared.PlayerPoppedPacketName,
+		Player:     i.getColor(player),
+		X:          int32(x),
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(popped)
+
+	i.finishMove(player)
+}
+
+// finishMove prüft nach einem Zug, ob jemand gewonnen hat oder das Spiel unentschieden ist. Ansonsten ist der nächste
+// Spieler an der Reihe.
+func (i *impl) finishMove(mover game.Player) {
+	lines := i.board.lines()
+
+	var winners []game.Player
+	var winnerColors []shared.Color
+	var cells []shared.Position
+	for _, player := range i.activePlayers() {
+		color := i.getColor(player)
+		if _, ok := lines[color]; !ok {
+			continue
+		}
+
+		// Hat der Spieler, der gezogen hat, eine Reihe, gewinnt er allein
+		if player == mover {
+			winners, winnerColors, cells = []game.Player{player}, []shared.Color{color}, lines[color]
+			break
+		}
+		winners = append(winners, player)
+		winnerColors = append(winnerColors, color)
+		cells = append(cells, lines[color]...)
+	}
+	if len(winners) != 0 {
+		i.party.EndGame(i.result(winners, shared.ResultDetails{
+			Winners: winnerColors,
+			Cells:   cells,
+		}))
+		return
+	}
+
+	if i.board.rules.PopOut {
+		key := i.board.key()
+		i.positions[key]++
+		if i.positions[key] >= maxRepetitions {
+			i.endInDraw()
+			return
+		}
+	}
+
+	i.nextTurn()
+}
+
+// nextTurn lässt den nächsten Spieler an die Reihe kommen, der einen Zug machen kann. Kann niemand mehr ziehen, endet
+// das Spiel unentschieden.
+func (i *impl) nextTurn() {
+	for range i.activePlayers() {
+		i.turns.Next()
+		if i.board.hasMove(i.getColor(i.turns.Current())) {
+			return
+		}
+	}
+
+	i.endInDraw()
+}
+
+func (i *impl) endInDraw() {
+	i.party.EndGame(i.result(i.activePlayers(), shared.ResultDetails{
+		Draw: true,
+	}))
+}
+
+func (i *impl) SkipTurn(game.Player) {
+	i.nextTurn()
+}
+
+func (i *impl) RandomMove(player game.Player) {
+	color := i.getColor(player)
+
+	type move struct {
+		x   int
+		pop bool
+	}
+	var moves []move
+	for x := 0; x < i.board.rules.Width; x++ {
+		if i.board.canPlace(x) {
+			moves = append(moves, move{x: x})
+		}
+		if i.board.canPop(color, x) {
+			moves = append(moves, move{x: x, pop: true})
+		}
+	}
+	if len(moves) == 0 {
+		i.nextTurn()
+		return
+	}
+
+	chosen := moves[i.party.Rand().Intn(len(moves))]
+	if chosen.pop {
+		i.pop(player, chosen.x)
+	} else {
+		i.place(player, chosen.x)
+	}
+}
+
+func (i *impl) Forfeit(player game.Player) {
+	i.eliminate(player)
+}
+
+func (i *impl) Snapshot(viewer game.Player) any {
+	ids := make([]int32, len(i.players))
+	for color, player := range i.players {
+		ids[color] = player.Id()
+	}
+
+	eliminated := make([]shared.Color, len(i.eliminated))
+	for j, player := range i.eliminated {
+		eliminated[j] = i.getColor(player)
+	}
+
+	return shared.Snapshot{
+		Rules:      i.board.rules,
+		Board:      i.board.clone().cells,
+		Players:    ids,
+		Eliminated: eliminated,
+		Moves:      i.moves,
+		Turn:       i.turns.State(),
+	}
+}
+
+func (i *impl) HandlePaused() {}
+
+func (i *impl) HandleResumed() {
+	i.turns.Resume()
+}
+
+func (i *impl) Tick() {
+	i.turns.Tick()
+}
+
+// checkOptions prüft, ob auf dem gewählten Spielfeld überhaupt eine Reihe mit der gewählten Länge gebildet werden kann.
+func checkOptions(options protocol.OptionValues) error {
+	rules := shared.RulesFromOptions(options)
+	if !rules.Valid() {
+		return fmt.Errorf("a row of %d pieces does not fit on a %dx%d board", rules.WinningLength, rules.Width, rules.Height)
+	}
+	return nil
+}
 
 var Type = game.Type{
-	Creator: create,
-	Name:    shared.Name,
+	Creator:            create,
+	Bot:                createBot,
+	Name:               shared.Name,
+	Description:        "Wer zuerst genug Steine in einer Reihe hat, gewinnt",
+	MinPlayers:         shared.MinPlayers,
+	MaxPlayers:         shared.MaxPlayers,
+	SupportsSpectators: true,
+	SoloOpponent:       true,
+	PlaylistPairings:   true,
+	Version:            shared.Version,
+	Options:            append(append(protocol.Options{}, shared.Options...), protocol.TurnOptions...),
+	CheckOptions:       checkOptions,
 }
@@ -0,0 +1,505 @@
+package connect4
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	shared "github.com/Lama06/Oinky-Party/connect4"
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+type testPlayer struct {
+	id int32
+}
+
+func (p *testPlayer) Id() int32         { return p.id }
+func (p *testPlayer) Name() string      { return "Spieler" }
+func (p *testPlayer) Team() int32       { return protocol.NoTeam }
+func (p *testPlayer) SendPacket([]byte) {}
+
+type testParty struct {
+	players []game.Player
+	result  *game.Result
+}
+
+func (p *testParty) Id() int32    { return 1 }
+func (p *testParty) Name() string { return "Party" }
+
+func (p *testParty) Players() map[int32]game.Player {
+	players := make(map[int32]game.Player, len(p.players))
+	for _, player := range p.players {
+		players[player.Id()] = player
+	}
+	return players
+}
+
+func (p *testParty) PlayerOrder() []game.Player             { return p.players }
+func (p *testParty) Teams() (map[int32][]game.Player, bool) { return nil, false }
+func (p *testParty) Rand() *rand.Rand                       { return rand.New(rand.NewSource(1)) }
+func (p *testParty) BroadcastPacket([]byte)                 {}
+func (p *testParty) EndGame(result game.Result)             { p.result = &result }
+
+// parseBoard liest ein Spielfeld aus Zeilen, die oberste Zeile zuerst. "." ist leer, "R", "Y", "G" und "B" sind die
+// Steine der Farben.
+func parseBoard(t *testing.T, rules shared.Rules, rows ...string) *board {
+	t.Helper()
+
+	if len(rows) != rules.Height {
+		t.Fatalf("expected %d rows, got %d", rules.Height, len(rows))
+	}
+
+	b := newBoard(rules)
+	for y, row := range rows {
+		if len(row) != rules.Width {
+			t.Fatalf("row %d: expected %d columns, got %d", y, rules.Width, len(row))
+		}
+		for x, r := range row {
+			if r == '.' {
+				continue
+			}
+			color := strings.IndexRune("RYGB", r)
+			if color == -1 {
+				t.Fatalf("invalid cell: %q", r)
+			}
+			b.cells[x][y] = shared.Color(color).ToCell()
+		}
+	}
+	return b
+}
+
+func sortPositions(positions []shared.Position) []shared.Position {
+	sorted := append([]shared.Position(nil), positions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	return sorted
+}
+
+func positions(coordinates ...int) []shared.Position {
+	result := make([]shared.Position, len(coordinates)/2)
+	for i := range result {
+		result[i] = shared.Position{X: coordinates[2*i], Y: coordinates[2*i+1]}
+	}
+	return result
+}
+
+var standardRules = shared.Rules{Width: 7, Height: 6, WinningLength: 4, Players: 2}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules shared.Rules
+		board []string
+		lines map[shared.Color][]shared.Position
+	}{
+		{
+			name:  "empty board",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				".......",
+				".......",
+			},
+			lines: map[shared.Color][]shared.Position{},
+		},
+		{
+			name:  "three in a row",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				"YYY....",
+				"RRR....",
+			},
+			lines: map[shared.Color][]shared.Position{},
+		},
+		{
+			name:  "horizontal",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				"..YYY..",
+				".RRRR..",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.RedColor: positions(1, 5, 2, 5, 3, 5, 4, 5),
+			},
+		},
+		{
+			name:  "vertical",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				"...Y...",
+				"...Y.R.",
+				"...Y.R.",
+				"...Y.R.",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.YellowColor: positions(3, 2, 3, 3, 3, 4, 3, 5),
+			},
+		},
+		{
+			name:  "rising diagonal",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				"...R...",
+				"..RY...",
+				".RYY...",
+				"RYYR...",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.RedColor: positions(0, 5, 1, 4, 2, 3, 3, 2),
+			},
+		},
+		{
+			name:  "falling diagonal",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				"...Y...",
+				"...RY..",
+				"...RRY.",
+				"...RRRY",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.YellowColor: positions(3, 2, 4, 3, 5, 4, 6, 5),
+			},
+		},
+		{
+			name:  "last column",
+			rules: standardRules,
+			board: []string{
+				".......",
+				"......Y",
+				"......Y",
+				"......Y",
+				".....RY",
+				".....RR",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.YellowColor: positions(6, 1, 6, 2, 6, 3, 6, 4),
+			},
+		},
+		{
+			name:  "top row",
+			rules: standardRules,
+			board: []string{
+				"...RRRR",
+				"...YYRY",
+				"...RRYR",
+				"...YYRY",
+				"...RRYR",
+				"...YYRY",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.RedColor: positions(3, 0, 4, 0, 5, 0, 6, 0),
+			},
+		},
+		{
+			name:  "line longer than the winning length",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				"YYYY...",
+				"RRRRR..",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.RedColor:    positions(0, 5, 1, 5, 2, 5, 3, 5, 4, 5),
+				shared.YellowColor: positions(0, 4, 1, 4, 2, 4, 3, 4),
+			},
+		},
+		{
+			name:  "winning length three",
+			rules: shared.Rules{Width: 5, Height: 4, WinningLength: 3, Players: 2},
+			board: []string{
+				".....",
+				".....",
+				"..G..",
+				"GGY..",
+			},
+			lines: map[shared.Color][]shared.Position{},
+		},
+		{
+			name:  "green on a larger board",
+			rules: shared.Rules{Width: 9, Height: 7, WinningLength: 4, Players: 3},
+			board: []string{
+				".........",
+				".........",
+				".........",
+				"........G",
+				".......GY",
+				"......GYR",
+				".....GRYR",
+			},
+			lines: map[shared.Color][]shared.Position{
+				shared.GreenColor: positions(5, 6, 6, 5, 7, 4, 8, 3),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines := parseBoard(t, test.rules, test.board...).lines()
+			if len(lines) != len(test.lines) {
+				t.Fatalf("expected lines for %d colors, got %v", len(test.lines), lines)
+			}
+			for color, cells := range test.lines {
+				if got := sortPositions(lines[color]); !reflect.DeepEqual(got, sortPositions(cells)) {
+					t.Errorf("color %d: expected cells %v, got %v", color, sortPositions(cells), got)
+				}
+			}
+		})
+	}
+}
+
+type testMove struct {
+	color shared.Color
+	x     int
+	pop   bool
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   shared.Rules
+		board   []string
+		moves   []testMove
+		draw    bool
+		winners []shared.Color
+		cells   []shared.Position
+	}{
+		{
+			name:  "no result yet",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				".......",
+				".......",
+			},
+			moves: []testMove{{color: shared.RedColor, x: 3}, {color: shared.YellowColor, x: 3}},
+		},
+		{
+			name:  "horizontal win",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				".......",
+				".......",
+				"YYY....",
+				"RRR....",
+			},
+			moves:   []testMove{{color: shared.RedColor, x: 3}},
+			winners: []shared.Color{shared.RedColor},
+			cells:   positions(0, 5, 1, 5, 2, 5, 3, 5),
+		},
+		{
+			name:  "vertical win in the last column",
+			rules: standardRules,
+			board: []string{
+				".......",
+				".......",
+				"......Y",
+				"......Y",
+				".....RY",
+				"....RRR",
+			},
+			moves:   []testMove{{color: shared.YellowColor, x: 6}},
+			winners: []shared.Color{shared.YellowColor},
+			cells:   positions(6, 1, 6, 2, 6, 3, 6, 4),
+		},
+		{
+			name:  "win in the top row",
+			rules: standardRules,
+			board: []string{
+				"...RRR.",
+				"...YYRY",
+				"...RRYR",
+				"...YYRY",
+				"...RRYR",
+				"...YYRY",
+			},
+			moves:   []testMove{{color: shared.RedColor, x: 6}},
+			winners: []shared.Color{shared.RedColor},
+			cells:   positions(3, 0, 4, 0, 5, 0, 6, 0),
+		},
+		{
+			name:  "full board draw",
+			rules: shared.Rules{Width: 4, Height: 4, WinningLength: 4, Players: 2},
+			board: []string{
+				".RYY",
+				"YYRR",
+				"RRYY",
+				"YYRR",
+			},
+			moves: []testMove{{color: shared.RedColor, x: 0}},
+			draw:  true,
+		},
+		{
+			name:  "pop creates lines for both players",
+			rules: shared.Rules{Width: 4, Height: 4, WinningLength: 4, Players: 2, PopOut: true},
+			board: []string{
+				"....",
+				"R...",
+				"YRRR",
+				"RYYY",
+			},
+			moves:   []testMove{{color: shared.RedColor, x: 0, pop: true}},
+			winners: []shared.Color{shared.RedColor},
+			cells:   positions(0, 2, 1, 2, 2, 2, 3, 2),
+		},
+		{
+			name:  "pop creates a line for the opponent",
+			rules: shared.Rules{Width: 4, Height: 4, WinningLength: 4, Players: 2, PopOut: true},
+			board: []string{
+				"....",
+				"R...",
+				"YRR.",
+				"RYYY",
+			},
+			moves:   []testMove{{color: shared.RedColor, x: 0, pop: true}},
+			winners: []shared.Color{shared.YellowColor},
+			cells:   positions(0, 3, 1, 3, 2, 3, 3, 3),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			party := &testParty{}
+			for id := 1; id <= test.rules.Players; id++ {
+				party.players = append(party.players, &testPlayer{id: int32(id)})
+			}
+
+			options := shared.Options.Defaults()
+			for name, value := range protocol.TurnOptions.Defaults() {
+				options[name] = value
+			}
+			options[shared.BoardWidthOption] = int32(test.rules.Width)
+			options[shared.BoardHeightOption] = int32(test.rules.Height)
+			options[shared.WinningLengthOption] = int32(test.rules.WinningLength)
+			if test.rules.PopOut {
+				options[shared.PopOutOption] = 1
+			}
+
+			g := create(party, options).(*impl)
+			g.board = parseBoard(t, g.board.rules, test.board...)
+			if lines := g.board.lines(); len(lines) != 0 {
+				t.Fatalf("the board already has lines: %v", lines)
+			}
+
+			for _, move := range test.moves {
+				if party.result != nil {
+					t.Fatal("the game ended before all moves were made")
+				}
+				player := party.players[move.color]
+				if move.pop {
+					if !g.board.canPop(move.color, move.x) {
+						t.Fatalf("cannot pop in column %d", move.x)
+					}
+					g.pop(player, move.x)
+				} else {
+					if !g.board.canPlace(move.x) {
+						t.Fatalf("cannot place in column %d", move.x)
+					}
+					g.place(player, move.x)
+				}
+			}
+
+			if !test.draw && test.winners == nil {
+				if party.result != nil {
+					t.Fatalf("expected no result, got %+v", party.result)
+				}
+				return
+			}
+			if party.result == nil {
+				t.Fatal("expected the game to end")
+			}
+
+			details, ok := party.result.Details.(shared.ResultDetails)
+			if !ok {
+				t.Fatalf("expected result details, got %T", party.result.Details)
+			}
+			if details.Draw != test.draw {
+				t.Errorf("expected draw %v, got %v", test.draw, details.Draw)
+			}
+			if !reflect.DeepEqual(details.Winners, test.winners) {
+				t.Errorf("expected winners %v, got %v", test.winners, details.Winners)
+			}
+			if got := sortPositions(details.Cells); !reflect.DeepEqual(got, sortPositions(test.cells)) {
+				t.Errorf("expected cells %v, got %v", sortPositions(test.cells), got)
+			}
+			if len(details.Moves) != len(test.moves) {
+				t.Errorf("expected %d moves, got %d", len(test.moves), len(details.Moves))
+			}
+
+			if !test.draw {
+				var winnerIds []int32
+				for _, winner := range test.winners {
+					winnerIds = append(winnerIds, party.players[winner].Id())
+				}
+				if !reflect.DeepEqual(party.result.Ranking[0], winnerIds) {
+					t.Errorf("expected the winners %v first, got ranking %v", winnerIds, party.result.Ranking)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckOptions(t *testing.T) {
+	tests := []struct {
+		name                         string
+		width, height, winningLength int32
+		valid                        bool
+	}{
+		{name: "standard", width: 7, height: 6, winningLength: 4, valid: true},
+		{name: "row fits in width only", width: 6, height: 4, winningLength: 6, valid: true},
+		{name: "row fits in neither direction", width: 4, height: 4, winningLength: 5, valid: false},
+		{name: "longest row on the smallest board", width: 4, height: 4, winningLength: 6, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := shared.Options.Defaults()
+			for name, value := range protocol.TurnOptions.Defaults() {
+				options[name] = value
+			}
+			options[shared.BoardWidthOption] = test.width
+			options[shared.BoardHeightOption] = test.height
+			options[shared.WinningLengthOption] = test.winningLength
+
+			_, err := Type.ValidateOptions(options)
+			if test.valid && err != nil {
+				t.Errorf("expected the options to be valid, got %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected the options to be rejected")
+			}
+		})
+	}
+}
@@ -58,12 +58,12 @@ func (p *player) toUpdateData() shared.PlayerUpdateData {
 	}
 }
 
-func randomObstacleFreeSpace() (lowerY, upperY float64) {
-	lowerY = rand.Float64()
-	if lowerY-shared.ObstacleFreeSpaceHeight < 0 {
-		lowerY = 1 - shared.ObstacleFreeSpaceHeight
+func randomObstacleFreeSpace(random *rand.Rand, freeSpaceHeight float64) (lowerY, upperY float64) {
+	lowerY = random.Float64()
+	if lowerY-freeSpaceHeight < 0 {
+		lowerY = 1 - freeSpaceHeight
 	}
-	upperY = lowerY - shared.ObstacleFreeSpaceHeight
+	upperY = lowerY - freeSpaceHeight
 	return
 }
 
@@ -71,6 +71,7 @@ type obstacle struct {
 	freeSpaceLowerY float64 // Die Y Koordinate der oberen Kante des unteren Teils des Hindernisses
 	freeSpaceUpperY float64 // Die Y Koordinate der unteren Kante des oberen Teils des Hindernisses
 	posX            float64 // Die X Position der linken Kante des Hindernisses
+	speed           float64 // Die Geschwindigkeit, mit der posX pro Tick erhöht wird
 }
 
 func (o *obstacle) isOutsideWorld() bool {
@@ -78,7 +79,7 @@ func (o *obstacle) isOutsideWorld() bool {
 }
 
 func (o *obstacle) tick() {
-	o.posX += shared.ObstacleSpeed
+	o.posX += o.speed
 }
 
 func (o obstacle) toUpdateData() shared.ObstacleUpdateData {
@@ -91,19 +92,28 @@ func (o obstacle) toUpdateData() shared.ObstacleUpdateData {
 
 type impl struct {
 	party                  game.Party
+	obstacleSpawnRate      int
+	obstacleFreeSpace      float64
+	obstacleSpeed          float64
 	alivePlayers           map[int32]*player
 	ticksUntilNextObstacle int
 	obstacleCount          int32
 	obstacles              []*obstacle
+	deadPlayers            [][]int32       // Die IDs der gestorbenen Spieler. Spieler, die im selben Tick gestorben sind, stehen im selben Eintrag.
+	scores                 map[int32]int32 // Die Anzahl der Hindernisse, die ein Spieler erreicht hat
 }
 
 var _ game.Game = (*impl)(nil)
 
-func create(party game.Party) game.Game {
+func create(party game.Party, options protocol.OptionValues) game.Game {
 	return &impl{
 		party:                  party,
+		obstacleSpawnRate:      shared.ObstacleSpawnRate(options),
+		obstacleFreeSpace:      shared.ObstacleFreeSpaceHeight(options),
+		obstacleSpeed:          shared.ObstacleSpeed(options),
 		alivePlayers:           make(map[int32]*player, len(party.Players())),
-		ticksUntilNextObstacle: shared.ObstacleSpawnRate,
+		ticksUntilNextObstacle: shared.ObstacleSpawnRate(options),
+		scores:                 make(map[int32]int32, len(party.Players())),
 	}
 }
 
@@ -122,10 +132,12 @@ func (i *impl) HandleGameStarted() {
 func (i *impl) HandleGameEnded() {}
 
 func (i *impl) HandlePlayerLeft(player game.Player) {
-	delete(i.alivePlayers, player.Id())
+	if _, alive := i.alivePlayers[player.Id()]; !alive {
+		return
+	}
 
-	if len(i.alivePlayers) == 0 {
-		i.party.EndGame()
+	if gameEnded := i.killPlayers([]int32{player.Id()}); gameEnded {
+		i.party.EndGame(i.result())
 	}
 }
 
@@ -152,7 +164,7 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 
 func (i *impl) Tick() {
 	if gameEnded := i.tickPlayers(); gameEnded {
-		i.party.EndGame()
+		i.party.EndGame(i.result())
 		return
 	}
 	i.tickObstacles()
@@ -183,29 +195,70 @@ func (i *impl) broadcastUpdatePacket() {
 }
 
 func (i *impl) tickPlayers() (gameEnded bool) {
+	var died []int32
 	for id, player := range i.alivePlayers {
 		player.tick()
 
 		if player.isOutsideWorld() || player.isTouchingObstacle(i.obstacles) {
-			if gameEnded := i.killPlayer(id); gameEnded {
-				return true
-			}
+			died = append(died, id)
 		}
 	}
 
-	return false
+	if len(died) == 0 {
+		return false
+	}
+
+	return i.killPlayers(died)
 }
 
-func (i *impl) killPlayer(id int32) (gameEnded bool) {
-	delete(i.alivePlayers, id)
+func (i *impl) killPlayers(ids []int32) (gameEnded bool) {
+	for _, id := range ids {
+		delete(i.alivePlayers, id)
+		i.scores[id] = i.obstacleCount
+	}
+	i.deadPlayers = append(i.deadPlayers, ids)
 	return len(i.alivePlayers) == 0
 }
 
+// result erstellt das Ergebnis des Spieles. Wer später gestorben ist, ist besser platziert.
+// Wird in Teams gespielt, ist die Punktzahl eines Teams die Summe der erreichten Hindernisse seiner Spieler.
+func (i *impl) result() game.Result {
+	if teams, ok := i.party.Teams(); ok {
+		teamScores := make(map[int32]int, len(teams))
+		for team, members := range teams {
+			for _, member := range members {
+				if _, alive := i.alivePlayers[member.Id()]; alive {
+					teamScores[team] += int(i.obstacleCount)
+				} else {
+					teamScores[team] += int(i.scores[member.Id()])
+				}
+			}
+		}
+		return game.TeamResult(teams, teamScores)
+	}
+
+	ranking := make([][]int32, 0, len(i.deadPlayers)+1)
+
+	if len(i.alivePlayers) != 0 {
+		alive := make([]int32, 0, len(i.alivePlayers))
+		for id := range i.alivePlayers {
+			alive = append(alive, id)
+		}
+		ranking = append(ranking, alive)
+	}
+
+	for index := len(i.deadPlayers) - 1; index >= 0; index-- {
+		ranking = append(ranking, i.deadPlayers[index])
+	}
+
+	return game.Result{Ranking: ranking}
+}
+
 func (i *impl) tickObstacles() {
 	i.ticksUntilNextObstacle--
 
 	if i.ticksUntilNextObstacle <= 0 {
-		i.ticksUntilNextObstacle = shared.ObstacleSpawnRate
+		i.ticksUntilNextObstacle = i.obstacleSpawnRate
 
 		i.spawnNewObstacle()
 	}
@@ -220,12 +273,13 @@ func (i *impl) tickObstacles() {
 func (i *impl) spawnNewObstacle() {
 	i.obstacleCount++
 
-	freeSpaceLowerY, freeSpaceUpperY := randomObstacleFreeSpace()
+	freeSpaceLowerY, freeSpaceUpperY := randomObstacleFreeSpace(i.party.Rand(), i.obstacleFreeSpace)
 
 	newObstacle := &obstacle{
 		freeSpaceLowerY: freeSpaceLowerY,
 		freeSpaceUpperY: freeSpaceUpperY,
 		posX:            1,
+		speed:           i.obstacleSpeed,
 	}
 
 	i.obstacles = append(i.obstacles, newObstacle)
@@ -242,6 +296,12 @@ func (i *impl) removeObstaclesOutsideWorld() {
 }
 
 var Type = game.Type{
-	Creator: create,
-	Name:    shared.Name,
+	Creator:            create,
+	Name:               shared.Name,
+	Description:        "Fliege so lange wie möglich durch die Hindernisse",
+	MinPlayers:         1,
+	SupportsSpectators: true,
+	TickRate:           1000 / protocol.TickSpeed,
+	Version:            shared.Version,
+	Options:            shared.Options,
 }
@@ -0,0 +1,59 @@
+package flappyoinky
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	shared "github.com/Lama06/Oinky-Party/flappyoinky"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+type testParty struct {
+	random *rand.Rand
+}
+
+func (p *testParty) Id() int32                              { return 1 }
+func (p *testParty) Name() string                           { return "Party" }
+func (p *testParty) Players() map[int32]game.Player         { return nil }
+func (p *testParty) PlayerOrder() []game.Player             { return nil }
+func (p *testParty) Teams() (map[int32][]game.Player, bool) { return nil, false }
+func (p *testParty) Rand() *rand.Rand                       { return p.random }
+func (p *testParty) BroadcastPacket([]byte)                 {}
+func (p *testParty) EndGame(game.Result)                    {}
+
+// obstacleLayout lässt ein Spiel ohne Spieler laufen und gibt die Lücken aller Hindernisse zurück, die dabei entstanden
+// sind.
+func obstacleLayout(seed int64, ticks int) [][2]float64 {
+	i := create(&testParty{random: rand.New(rand.NewSource(seed))}, shared.Options.Defaults()).(*impl)
+	i.HandleGameStarted()
+
+	var layout [][2]float64
+	for tick := 0; tick < ticks; tick++ {
+		i.Tick()
+		if len(i.obstacles) != 0 {
+			newest := i.obstacles[len(i.obstacles)-1]
+			if int(i.obstacleCount) > len(layout) {
+				layout = append(layout, [2]float64{newest.freeSpaceUpperY, newest.freeSpaceLowerY})
+			}
+		}
+	}
+	return layout
+}
+
+func TestObstaclesAreSeeded(t *testing.T) {
+	const ticks = 1000
+
+	first := obstacleLayout(42, ticks)
+	if len(first) < 2 {
+		t.Fatalf("expected several obstacles, got %d", len(first))
+	}
+
+	if second := obstacleLayout(42, ticks); !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed produced different obstacles:\n%v\n%v", first, second)
+	}
+
+	if other := obstacleLayout(43, ticks); reflect.DeepEqual(first, other) {
+		t.Errorf("different seeds produced the same obstacles: %v", first)
+	}
+}
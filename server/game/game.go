@@ -1,17 +1,162 @@
 package game
 
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+)
+
 type Type struct {
-	Creator Creator
-	Name    string
+	Creator            Creator
+	Name               string
+	Description        string
+	MinPlayers         int
+	MaxPlayers         int // 0 bedeutet, dass es keine Obergrenze gibt
+	SupportsSpectators bool
+	Bot                BotCreator // nil, wenn das Spiel nicht mit Bots gespielt werden kann
+	SoloOpponent       bool       // Ob in einer Party mit nur einem Spieler automatisch ein Bot als Gegner hinzugefügt wird
+	PlaylistPairings   bool       // Ob in Playlists jeder Spieler einzeln gegen jeden anderen spielt, statt alle gemeinsam
+	TickRate           int        // Wie oft das Spiel pro Sekunde Updates an die Clients sendet. 0 bei rundenbasierten Spielen.
+	Version            int
+	Options            protocol.Options
+	CheckOptions       func(options protocol.OptionValues) error // Prüft, ob die Einstellungen zusammenpassen, kann nil sein
+}
+
+// ValidateOptions prüft die Einstellungen anhand von Options und CheckOptions und ergänzt fehlende Werte mit ihrem
+// Standardwert.
+func (t Type) ValidateOptions(values protocol.OptionValues) (protocol.OptionValues, error) {
+	values, err := t.Options.Validate(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.CheckOptions != nil {
+		err = t.CheckOptions(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (t Type) ToData() protocol.GameTypeData {
+	return protocol.GameTypeData{
+		Name:               t.Name,
+		Description:        t.Description,
+		MinPlayers:         int32(t.MinPlayers),
+		MaxPlayers:         int32(t.MaxPlayers),
+		SupportsSpectators: t.SupportsSpectators,
+		SupportsBots:       t.Bot != nil,
+		SoloOpponent:       t.SoloOpponent && t.Bot != nil,
+		TickRate:           int32(t.TickRate),
+		Version:            int32(t.Version),
+		Options:            t.Options,
+	}
+}
+
+// Creator erstellt ein neues Spiel. Die Einstellungen wurden bereits anhand von Type.Options geprüft und vervollständigt.
+type Creator func(party Party, options protocol.OptionValues) Game
+
+// Result beschreibt den Ausgang eines Spieles.
+// Ranking enthält die IDs der Spieler nach ihrer Platzierung geordnet. Der erste Eintrag enthält die Gewinner.
+// Spieler, die sich eine Platzierung teilen, stehen im selben Eintrag.
+// Wurde das Spiel in Teams gespielt, enthält TeamRanking die Teams nach ihrer Platzierung geordnet.
+// Details kann spielspezifische Informationen über das Ergebnis enthalten und wird als JSON an die Clients gesendet.
+type Result struct {
+	Ranking     [][]int32
+	TeamRanking [][]int32
+	Details     any
+}
+
+// AbortedResult ist das Ergebnis eines Spieles, das ohne Gewinner beendet wurde.
+var AbortedResult = Result{}
+
+// WinnerResult erstellt das Ergebnis eines Spieles, bei dem ein Spieler gegen alle anderen Spieler gewonnen hat.
+func WinnerResult(winner Player, losers ...Player) Result {
+	loserIds := make([]int32, len(losers))
+	for i, loser := range losers {
+		loserIds[i] = loser.Id()
+	}
+
+	return Result{
+		Ranking: [][]int32{{winner.Id()}, loserIds},
+	}
+}
+
+// DrawResult erstellt das Ergebnis eines Spieles, das unentschieden ausgegangen ist.
+func DrawResult(players ...Player) Result {
+	ids := make([]int32, len(players))
+	for i, player := range players {
+		ids[i] = player.Id()
+	}
+
+	return Result{
+		Ranking: [][]int32{ids},
+	}
+}
+
+// TeamResult erstellt das Ergebnis eines Spieles, bei dem die Teams nach ihren Punkten platziert werden.
+// Alle Spieler eines Teams teilen sich die Platzierung ihres Teams.
+func TeamResult(teams map[int32][]Player, scores map[int32]int) Result {
+	teamIds := make([]int32, 0, len(teams))
+	for team := range teams {
+		teamIds = append(teamIds, team)
+	}
+	sort.Slice(teamIds, func(i, j int) bool {
+		if scores[teamIds[i]] != scores[teamIds[j]] {
+			return scores[teamIds[i]] > scores[teamIds[j]]
+		}
+		return teamIds[i] < teamIds[j]
+	})
+
+	var result Result
+	for i, team := range teamIds {
+		members := make([]int32, len(teams[team]))
+		for j, member := range teams[team] {
+			members[j] = member.Id()
+		}
+
+		if i != 0 && scores[teamIds[i-1]] == scores[team] {
+			lastPlace := len(result.Ranking) - 1
+			result.Ranking[lastPlace] = append(result.Ranking[lastPlace], members...)
+			result.TeamRanking[lastPlace] = append(result.TeamRanking[lastPlace], team)
+			continue
+		}
+
+		result.Ranking = append(result.Ranking, members)
+		result.TeamRanking = append(result.TeamRanking, []int32{team})
+	}
+	return result
 }
 
-type Creator func(party Party) Game
+func (r Result) ToData() protocol.GameResultData {
+	var details json.RawMessage
+	if r.Details != nil {
+		var err error
+		details, err = json.Marshal(r.Details)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return protocol.GameResultData{
+		Ranking:     r.Ranking,
+		TeamRanking: r.TeamRanking,
+		Details:     details,
+	}
+}
 
 type Player interface {
 	Id() int32
 
 	Name() string
 
+	// Team gibt das Team des Spielers zurück oder protocol.NoTeam, wenn er keinem Team zugeordnet ist.
+	Team() int32
+
 	SendPacket(data []byte)
 }
 
@@ -20,13 +165,96 @@ type Party interface {
 
 	Name() string
 
+	// Players gibt die Spieler zurück, die am aktuellen Spiel teilnehmen.
+	// Spieler der Party, die nur zuschauen, sind nicht enthalten.
 	Players() map[int32]Player
 
+	// PlayerOrder gibt die Spieler, die am aktuellen Spiel teilnehmen, in der Reihenfolge zurück, in der sie beim
+	// Start des Spieles angegeben wurden. In rundenbasierten Spielen beginnt der erste Spieler.
+	PlayerOrder() []Player
+
+	// Teams gruppiert die Spieler, die am aktuellen Spiel teilnehmen, nach ihren Teams.
+	// ok ist false, wenn nicht alle Spieler einem Team zugeordnet sind oder es weniger als zwei Teams gibt.
+	Teams() (teams map[int32][]Player, ok bool)
+
+	// Rand gibt den Zufallsgenerator des aktuellen Spieles zurück. Er wird zu Beginn jedes Spieles mit einem neuen
+	// Seed erstellt, damit sich ein Spiel mit demselben Seed genau wiederholen lässt. Spiele sollten keinen anderen
+	// Zufall verwenden.
+	Rand() *rand.Rand
+
+	// BroadcastPacket sendet das Packet an alle Spieler der Party, also auch an die Zuschauer.
 	BroadcastPacket([]byte)
 
-	EndGame()
+	EndGame(result Result)
 }
 
+// GroupByTeam gruppiert die Spieler nach ihren Teams.
+// ok ist false, wenn nicht alle Spieler einem Team zugeordnet sind oder es weniger als zwei Teams gibt.
+func GroupByTeam(players map[int32]Player) (teams map[int32][]Player, ok bool) {
+	ids := make([]int32, 0, len(players))
+	for id := range players {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	teams = make(map[int32][]Player)
+	for _, id := range ids {
+		player := players[id]
+		if player.Team() == protocol.NoTeam {
+			return nil, false
+		}
+		teams[player.Team()] = append(teams[player.Team()], player)
+	}
+
+	if len(teams) < 2 {
+		return nil, false
+	}
+
+	return teams, true
+}
+
+// Snapshotter kann von Spielen implementiert werden, damit Spieler, die während des Spieles dazukommen, den
+// aktuellen Zustand des Spieles erhalten. Ohne Snapshot kennen sie nur die Packets, die nach ihrem Beitritt gesendet
+// werden.
+type Snapshotter interface {
+	// Snapshot gibt den Zustand des Spieles so zurück, wie ihn der Spieler sehen darf. Geheime Informationen, wie
+	// die Schiffe des Gegners, dürfen nicht enthalten sein. Der Snapshot wird als JSON an den Client gesendet.
+	// nil bedeutet, dass kein Snapshot gesendet wird.
+	Snapshot(viewer Player) any
+}
+
+// Pausable kann von Spielen implementiert werden, die beim Pausieren oder Fortsetzen etwas tun müssen.
+// Während das Spiel pausiert ist, wird Tick nicht aufgerufen und die Packets der Spieler werden nicht an das Spiel
+// weitergegeben.
+type Pausable interface {
+	HandlePaused()
+
+	HandleResumed()
+}
+
+// Bot ist die Strategie, mit der ein Bot ein Spiel spielt.
+// Ein Bot erhält dieselben Packets wie ein menschlicher Spieler und macht seine Züge, indem er Packets an das Spiel
+// sendet. Diese werden vom Spiel genauso geprüft wie die Packets der anderen Spieler.
+type Bot interface {
+	// HandlePacket wird mit jedem Packet aufgerufen, das an den Bot gesendet wird.
+	// Unbekannte Packets sollten ignoriert werden, da der Bot auch die Packets der Party erhält.
+	HandlePacket(data []byte) error
+
+	Tick()
+}
+
+// StoppableBot kann von Bots implementiert werden, die im Hintergrund rechnen. Stop wird aufgerufen, wenn das Spiel
+// endet, damit der Bot seine Berechnungen abbrechen kann.
+type StoppableBot interface {
+	Bot
+	Stop()
+}
+
+// BotCreator erstellt die Strategie eines Bots zu Beginn eines Spieles.
+// random ist ein eigener Zufallsgenerator des Bots, der aus dem Seed des Spieles erstellt wird.
+// Mit send kann der Bot Packets an das Spiel senden. Diese werden erst im nächsten Tick verarbeitet.
+type BotCreator func(self Player, options protocol.OptionValues, random *rand.Rand, send func(data []byte)) Bot
+
 type Game interface {
 	HandleGameStarted()
 
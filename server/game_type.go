@@ -1,6 +1,7 @@
 package server
 
 import (
+	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/Lama06/Oinky-Party/server/connect4"
 	"github.com/Lama06/Oinky-Party/server/flappyoinky"
 	"github.com/Lama06/Oinky-Party/server/game"
@@ -13,6 +14,14 @@ var gameTypes = []game.Type{
 	schiffe_versenken.Type,
 }
 
+func gameTypesToData() []protocol.GameTypeData {
+	data := make([]protocol.GameTypeData, len(gameTypes))
+	for i, t := range gameTypes {
+		data[i] = t.ToData()
+	}
+	return data
+}
+
 func gameTypeByName(name string) (t game.Type, ok bool) {
 	for _, t := range gameTypes {
 		if t.Name == name {
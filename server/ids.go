@@ -0,0 +1,73 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// idAllocator vergibt zufällige IDs, die nicht erraten werden können und sich nicht wiederholen, solange sie
+// vergeben sind.
+type idAllocator struct {
+	used map[int32]struct{}
+}
+
+func newIdAllocator() *idAllocator {
+	return &idAllocator{
+		used: map[int32]struct{}{},
+	}
+}
+
+func randomId() int32 {
+	var buffer [4]byte
+	_, err := rand.Read(buffer[:])
+	if err != nil {
+		panic(err)
+	}
+	// Die IDs sind positiv, weil 0 in den Packets für "kein Spieler" steht
+	return int32(binary.BigEndian.Uint32(buffer[:]) & 0x7fffffff)
+}
+
+func (a *idAllocator) allocate() int32 {
+	for {
+		id := randomId()
+		if id == 0 {
+			continue
+		}
+
+		if _, used := a.used[id]; used {
+			continue
+		}
+
+		a.used[id] = struct{}{}
+		return id
+	}
+}
+
+// release gibt eine ID wieder frei, nachdem der Spieler oder die Party entfernt wurde.
+func (a *idAllocator) release(id int32) {
+	delete(a.used, id)
+}
+
+const sessionTokenLength = 32
+
+// newSessionToken erstellt ein geheimes Token, mit dem ein Spieler nach einem Verbindungsabbruch seine Sitzung
+// fortsetzen kann. Anders als die ID wird das Token niemals an andere Spieler gesendet.
+func newSessionToken() string {
+	var buffer [sessionTokenLength]byte
+	_, err := rand.Read(buffer[:])
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buffer[:])
+}
+
+// session ist die Sitzung eines Spielers, dessen Verbindung abgebrochen ist. Sie kann bis zum Ablauf mit dem
+// protocol.ResumeSessionPacket fortgesetzt werden.
+type session struct {
+	id      int32
+	name    string
+	party   *party // Die Party, in der sich der Spieler befand, oder nil
+	expires time.Time
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// Wenn genug Spieler für ein Spiel warten, aber die maximale Anzahl noch nicht erreicht ist, wird noch so lange
+// auf weitere Spieler gewartet.
+const matchmakingWaitTime = 10 * time.Second
+
+type matchmakingEntry struct {
+	player   *player
+	joinedAt time.Time
+}
+
+type matchmakingQueue struct {
+	gameType game.Type
+	entries  []matchmakingEntry
+}
+
+func (q *matchmakingQueue) maxGroupSize() int {
+	if q.gameType.MaxPlayers == 0 || q.gameType.MaxPlayers > protocol.MaxPartySize {
+		return protocol.MaxPartySize
+	}
+	return q.gameType.MaxPlayers
+}
+
+// nextGroup gibt die Spieler zurück, die zusammen ein Spiel beginnen können.
+func (q *matchmakingQueue) nextGroup() (group []*player, found bool) {
+	if len(q.entries) == 0 || len(q.entries) < q.gameType.MinPlayers {
+		return nil, false
+	}
+
+	size := len(q.entries)
+	if size > q.maxGroupSize() {
+		size = q.maxGroupSize()
+	}
+
+	if size < q.maxGroupSize() && time.Since(q.entries[0].joinedAt) < matchmakingWaitTime {
+		return nil, false
+	}
+
+	group = make([]*player, size)
+	for i := 0; i < size; i++ {
+		group[i] = q.entries[i].player
+	}
+	q.entries = q.entries[size:]
+	return group, true
+}
+
+func (q *matchmakingQueue) remove(target *player) (removed bool) {
+	for i, entry := range q.entries {
+		if entry.player == target {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *matchmakingQueue) sendStatus() {
+	for i, entry := range q.entries {
+		status, err := json.Marshal(protocol.MatchmakingStatusPacket{
+			PacketName: protocol.MatchmakingStatusPacketName,
+			GameType:   q.gameType.Name,
+			Position:   int32(i + 1),
+			QueueSize:  int32(len(q.entries)),
+		})
+		if err != nil {
+			panic(err)
+		}
+		entry.player.SendPacket(status)
+	}
+}
+
+func (s *server) joinMatchmaking(target *player, packet protocol.FindMatchPacket) error {
+	t, ok := gameTypeByName(packet.GameType)
+	if !ok {
+		return fmt.Errorf("cannot find game type %s", packet.GameType)
+	}
+
+	if s.parties.byPlayer(target) != nil {
+		return errors.New("player is already in a party")
+	}
+
+	s.leaveMatchmaking(target)
+	s.unsubscribePartyBrowser(target)
+
+	queue, ok := s.matchmaking[t.Name]
+	if !ok {
+		queue = &matchmakingQueue{gameType: t}
+		s.matchmaking[t.Name] = queue
+	}
+	queue.entries = append(queue.entries, matchmakingEntry{
+		player:   target,
+		joinedAt: time.Now(),
+	})
+	queue.sendStatus()
+
+	return nil
+}
+
+func (s *server) leaveMatchmaking(target *player) {
+	for _, queue := range s.matchmaking {
+		if queue.remove(target) {
+			queue.sendStatus()
+		}
+	}
+}
+
+func (s *server) tickMatchmaking() {
+	for _, queue := range s.matchmaking {
+		for len(s.parties) < s.config.maxParties {
+			group, found := queue.nextGroup()
+			if !found {
+				break
+			}
+
+			s.createMatch(queue.gameType, group)
+			queue.sendStatus()
+		}
+	}
+}
+
+func (s *server) createMatch(t game.Type, players []*player) {
+	party := newParty(s, s.partyIds.allocate(), "Schnelles Spiel", players[0])
+	s.parties[party.id] = party
+	s.notifyPartyCreated(party)
+
+	for _, player := range players {
+		party.addPlayer(player)
+	}
+
+	err := party.startGame(t, party.playersSorted(), t.Options.Defaults())
+	if err != nil {
+		log.Println(fmt.Errorf("failed to start the game of a match: %w", err))
+
+		// Die Spieler sollen nicht in einer Party ohne Spiel festsitzen. Sie kehren zum Startbildschirm zurück.
+		for _, player := range players {
+			party.removePlayer(player)
+		}
+		if s.parties[party.id] == party {
+			s.removeParty(party)
+		}
+	}
+}
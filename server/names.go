@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+)
+
+func (s *server) changeName(p *player, newName string) {
+	if s.parties.byPlayer(p) != nil {
+		p.sendNameRejected(protocol.NameChangeInParty)
+		return
+	}
+
+	newName = protocol.NormalizeName(newName)
+	if reason, ok := protocol.ValidateName(newName); !ok {
+		p.sendNameRejected(reason)
+		return
+	}
+
+	log.Printf("player %s(%d) changed their name to %s\n", p.name, p.id, newName)
+	p.name = newName
+	p.sendNameChanged()
+}
+
+func (p *player) sendNameChanged() {
+	nameChanged, err := json.Marshal(protocol.NameChangedPacket{
+		PacketName: protocol.NameChangedPacketName,
+		Name:       p.name,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.SendPacket(nameChanged)
+}
+
+func (p *player) sendNameRejected(reason protocol.NameRejectionReason) {
+	nameRejected, err := json.Marshal(protocol.NameRejectedPacket{
+		PacketName: protocol.NameRejectedPacketName,
+		Reason:     reason,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.SendPacket(nameRejected)
+}
+
+// nameWithSuffix hängt eine Nummer an den Namen an und kürzt ihn, falls er sonst zu lang wäre.
+func nameWithSuffix(name string, number int) string {
+	suffix := fmt.Sprintf(" (%d)", number)
+	maxLength := protocol.MaxNameLength - utf8.RuneCountInString(suffix)
+
+	runes := []rune(name)
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	return protocol.NormalizeName(string(runes)) + suffix
+}
+
+// uniqueName gibt einen Namen zurück, den noch kein Spieler der Party hat. Ist der Name bereits vergeben, wird eine
+// Nummer angehängt.
+func (p *party) uniqueName(name string) string {
+	names := make(map[string]struct{}, len(p.players))
+	for _, player := range p.players {
+		names[player.name] = struct{}{}
+	}
+
+	if _, taken := names[name]; !taken {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		candidate := nameWithSuffix(name, i)
+		if _, taken := names[candidate]; !taken {
+			return candidate
+		}
+	}
+}
@@ -4,31 +4,76 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
 
 	"github.com/Lama06/Oinky-Party/protocol"
 	"github.com/Lama06/Oinky-Party/server/game"
 )
 
 type party struct {
-	server      *server
-	id          int32
-	name        string
-	players     map[int32]*player
-	currentGame game.Game
+	server         *server
+	id             int32
+	name           string
+	host           *player
+	players        map[int32]*player
+	participants   map[int32]*player // Die Spieler, die am aktuellen Spiel teilnehmen
+	playerOrder    []*player         // Die Spieler, die am aktuellen Spiel teilnehmen, in der Reihenfolge des Starts
+	playlist       *playlist
+	currentGame    game.Game
+	currentType    game.Type
+	currentOptions protocol.OptionValues
+	seed           int64
+	random         *rand.Rand      // Der Zufallsgenerator des aktuellen Spieles
+	recorder       *replayRecorder // nil, wenn das aktuelle Spiel nicht aufgezeichnet wird
+	emptySince     time.Time       // Der Zeitpunkt, an dem der letzte Spieler die Party verlassen hat
+	rematch        *rematch        // nil, wenn das letzte Spiel nicht erneut gespielt werden kann
+	paused         bool
+	pausedBy       int32
+	pauseVotes     map[int32]struct{} // Die Spieler, die dafür gestimmt haben, das Spiel zu pausieren oder fortzusetzen
 }
 
 var _ game.Party = (*party)(nil)
 
+func newParty(s *server, id int32, name string, host *player) *party {
+	p := &party{
+		server:  s,
+		id:      id,
+		name:    name,
+		host:    host,
+		players: map[int32]*player{},
+	}
+	p.playlist = newPlaylist(p)
+	return p
+}
+
 func (p *party) toData() protocol.PartyData {
 	players := make([]protocol.PlayerData, 0, len(p.players))
 	for _, player := range p.players {
 		players = append(players, player.toData())
 	}
 
+	var host int32
+	if p.host != nil {
+		host = p.host.id
+	}
+
+	var gameType string
+	if p.currentGame != nil {
+		gameType = p.currentType.Name
+	}
+
 	return protocol.PartyData{
-		Name:    p.name,
-		Id:      p.id,
-		Players: players,
+		Name:        p.name,
+		Id:          p.id,
+		Host:        host,
+		Players:     players,
+		Playlist:    p.playlist.toData(),
+		GameRunning: p.currentGame != nil,
+		GameType:    gameType,
+		Spectatable: p.spectatable(),
 	}
 }
 
@@ -39,6 +84,13 @@ func (p *party) BroadcastPacket(data []byte) {
 }
 
 func (p *party) addPlayer(target *player) {
+	target.team = protocol.NoTeam
+
+	if name := p.uniqueName(target.name); name != target.name {
+		target.name = name
+		target.sendNameChanged()
+	}
+
 	playerJoinedParty, err := json.Marshal(protocol.PlayerJoinedPartyPacket{
 		PacketName: protocol.PlayerJoinedPartyPacketName,
 		Player:     target.toData(),
@@ -49,6 +101,10 @@ func (p *party) addPlayer(target *player) {
 	p.BroadcastPacket(playerJoinedParty)
 
 	p.players[target.id] = target
+	p.emptySince = time.Time{}
+	if p.host == nil && target.bot == nil {
+		p.host = target // Die Party war leer
+	}
 
 	youJoinedParty, err := json.Marshal(protocol.YouJoinedPartyPacket{
 		PacketName: protocol.YouJoinedPartyPacketName,
@@ -58,6 +114,76 @@ func (p *party) addPlayer(target *player) {
 		panic(err)
 	}
 	target.SendPacket(youJoinedParty)
+
+	if p.currentGame != nil {
+		p.sendGameState(target)
+	} else if p.rematch != nil {
+		rematchStatus, err := json.Marshal(p.rematch.toPacket())
+		if err != nil {
+			panic(err)
+		}
+		target.SendPacket(rematchStatus)
+	}
+
+	p.server.notifyPartyUpdated(p)
+}
+
+// spectatable gibt an, ob Spieler der Party während des laufenden Spieles als Zuschauer beitreten können.
+func (p *party) spectatable() bool {
+	return p.currentGame != nil && p.currentType.SupportsSpectators
+}
+
+// sendGameState teilt einem Spieler, der während eines laufenden Spieles dazukommt, mit, welches Spiel läuft und
+// in welchem Zustand es sich befindet.
+func (p *party) sendGameState(target *player) {
+	target.SendPacket(p.gameStartedPacket())
+
+	if p.paused {
+		target.SendPacket(p.pauseStatusPacket())
+	}
+
+	snapshotter, ok := p.currentGame.(game.Snapshotter)
+	if !ok {
+		return
+	}
+
+	snapshot := snapshotter.Snapshot(target)
+	if snapshot == nil {
+		return
+	}
+
+	snapshotData, err := json.Marshal(snapshot)
+	if err != nil {
+		panic(err)
+	}
+
+	gameSnapshot, err := json.Marshal(protocol.GameSnapshotPacket{
+		PacketName: protocol.GameSnapshotPacketName,
+		Snapshot:   snapshotData,
+	})
+	if err != nil {
+		panic(err)
+	}
+	target.SendPacket(gameSnapshot)
+}
+
+func (p *party) gameStartedPacket() []byte {
+	participantIds := make([]int32, 0, len(p.participants))
+	for id := range p.participants {
+		participantIds = append(participantIds, id)
+	}
+	sort.Slice(participantIds, func(i, j int) bool { return participantIds[i] < participantIds[j] })
+
+	gameStarted, err := json.Marshal(protocol.GameStartedPacket{
+		PacketName: protocol.GameStartedPacketName,
+		GameType:   p.currentType.Name,
+		Players:    participantIds,
+		Options:    p.currentOptions,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return gameStarted
 }
 
 func (p *party) removePlayer(target *player) {
@@ -67,8 +193,11 @@ func (p *party) removePlayer(target *player) {
 			break
 		}
 	}
+	target.team = protocol.NoTeam
 
-	if p.currentGame != nil {
+	if _, participating := p.participants[target.id]; participating && p.currentGame != nil {
+		delete(p.participants, target.id)
+		delete(p.pauseVotes, target.id)
 		p.currentGame.HandlePlayerLeft(target)
 	}
 
@@ -88,6 +217,189 @@ func (p *party) removePlayer(target *player) {
 		panic(err)
 	}
 	target.SendPacket(youLeftParty)
+
+	if target.bot != nil {
+		p.server.playerIds.release(target.id)
+	}
+
+	if p.rematch != nil && p.rematch.hasPlayer(target) {
+		p.cancelRematch()
+	}
+
+	if p.host == target {
+		p.chooseNewHost()
+	}
+
+	if p.host == nil {
+		p.removeBots()
+		p.handleEmpty()
+		return
+	}
+
+	p.server.notifyPartyUpdated(p)
+}
+
+// handleEmpty beendet das laufende Spiel und die Playlist, wenn niemand mehr in der Party ist.
+func (p *party) handleEmpty() {
+	if p.currentGame != nil {
+		p.EndGame(game.AbortedResult)
+	}
+
+	if p.playlist.running {
+		p.playlist.stop()
+	}
+
+	p.server.handlePartyEmpty(p)
+}
+
+// chooseNewHost wählt den Spieler mit der niedrigsten ID als neuen Host. Bots können nicht Host werden.
+func (p *party) chooseNewHost() {
+	p.host = nil
+	for _, player := range p.playersSorted() {
+		if player.bot == nil {
+			p.host = player
+			break
+		}
+	}
+	if p.host == nil {
+		return
+	}
+
+	hostChanged, err := json.Marshal(protocol.HostChangedPacket{
+		PacketName: protocol.HostChangedPacketName,
+		Id:         p.host.id,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.BroadcastPacket(hostChanged)
+}
+
+// removeBots entfernt alle Bots, nachdem der letzte menschliche Spieler die Party verlassen hat.
+func (p *party) removeBots() {
+	for id, player := range p.players {
+		if player.bot != nil {
+			delete(p.players, id)
+			p.server.playerIds.release(id)
+		}
+	}
+}
+
+func (p *party) handleAddBotPacket() error {
+	if p.currentGame != nil {
+		return errors.New("cannot add bots while a game is running")
+	}
+
+	if len(p.players) >= protocol.MaxPartySize {
+		return errors.New("the party is full")
+	}
+
+	p.addPlayer(newBotPlayer(p.server, p.nextBotName()))
+
+	return nil
+}
+
+func (p *party) handleRemoveBotPacket(packet protocol.RemoveBotPacket) error {
+	if p.currentGame != nil {
+		return errors.New("cannot remove bots while a game is running")
+	}
+
+	target, ok := p.players[packet.Id]
+	if !ok {
+		return fmt.Errorf("player is not in the party: %d", packet.Id)
+	}
+
+	if target.bot == nil {
+		return fmt.Errorf("player is not a bot: %d", packet.Id)
+	}
+
+	p.removePlayer(target)
+
+	return nil
+}
+
+func (p *party) nextBotName() string {
+	names := make(map[string]struct{}, len(p.players))
+	for _, player := range p.players {
+		names[player.name] = struct{}{}
+	}
+
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("Bot %d", i)
+		if _, taken := names[name]; !taken {
+			return name
+		}
+	}
+}
+
+func (p *party) isHost(player *player) bool {
+	return p.host == player
+}
+
+func (p *party) playersSorted() []*player {
+	players := make([]*player, 0, len(p.players))
+	for _, player := range p.players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].id < players[j].id })
+	return players
+}
+
+func (p *party) broadcastTeamsChanged() {
+	teams := make(map[int32]int32, len(p.players))
+	for id, player := range p.players {
+		teams[id] = player.team
+	}
+
+	teamsChanged, err := json.Marshal(protocol.TeamsChangedPacket{
+		PacketName: protocol.TeamsChangedPacketName,
+		Teams:      teams,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.BroadcastPacket(teamsChanged)
+
+	p.server.notifyPartyUpdated(p)
+}
+
+func (p *party) handleSetTeamPacket(packet protocol.SetTeamPacket) error {
+	if p.currentGame != nil {
+		return errors.New("cannot change teams while a game is running")
+	}
+
+	target, ok := p.players[packet.Player]
+	if !ok {
+		return fmt.Errorf("player is not in the party: %d", packet.Player)
+	}
+
+	if packet.Team < protocol.NoTeam || packet.Team > protocol.MaxTeams {
+		return fmt.Errorf("invalid team: %d", packet.Team)
+	}
+
+	target.team = packet.Team
+	p.broadcastTeamsChanged()
+
+	return nil
+}
+
+func (p *party) handleBalanceTeamsPacket(packet protocol.BalanceTeamsPacket) error {
+	if p.currentGame != nil {
+		return errors.New("cannot change teams while a game is running")
+	}
+
+	if packet.Teams < 2 || packet.Teams > protocol.MaxTeams {
+		return fmt.Errorf("invalid number of teams: %d", packet.Teams)
+	}
+
+	players := p.playersSorted()
+	rand.Shuffle(len(players), func(i, j int) { players[i], players[j] = players[j], players[i] })
+	for i, player := range players {
+		player.team = int32(i)%packet.Teams + 1
+	}
+	p.broadcastTeamsChanged()
+
+	return nil
 }
 
 func (p *party) handleStartGamePacket(packet protocol.StartGamePacket) error {
@@ -96,26 +408,92 @@ func (p *party) handleStartGamePacket(packet protocol.StartGamePacket) error {
 		return fmt.Errorf("cannot find game type %s", packet.GameType)
 	}
 
+	if p.playlist.running {
+		return errors.New("the playlist is running")
+	}
+
+	players := p.playersSorted()
+	if len(players) == 1 && len(players) < t.MinPlayers && t.SoloOpponent && t.Bot != nil && p.currentGame == nil {
+		// Wer allein in der Party ist, spielt gegen einen Bot, der danach in der Party bleibt
+		opponent := newBotPlayer(p.server, p.nextBotName())
+		p.addPlayer(opponent)
+
+		err := p.startGame(t, p.playersSorted(), packet.Options)
+		if err != nil {
+			// Wenn das Spiel nicht startet, zum Beispiel wegen ungültiger Einstellungen, wird der Bot nicht gebraucht
+			p.removePlayer(opponent)
+		}
+		return err
+	}
+
+	return p.startGame(t, players, packet.Options)
+}
+
+func (p *party) startGame(t game.Type, participants []*player, options protocol.OptionValues) error {
 	if p.currentGame != nil {
 		return errors.New("a game is already running")
 	}
 
-	g := t.Creator(p)
+	options, err := t.ValidateOptions(options)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	if len(participants) < t.MinPlayers || (t.MaxPlayers != 0 && len(participants) > t.MaxPlayers) {
+		return fmt.Errorf("invalid number of players for %s: %d", t.Name, len(participants))
+	}
+
+	for _, participant := range participants {
+		if participant.bot != nil && t.Bot == nil {
+			return fmt.Errorf("%s cannot be played with bots", t.Name)
+		}
+	}
+
+	p.participants = make(map[int32]*player, len(participants))
+	for _, participant := range participants {
+		p.participants[participant.id] = participant
+	}
+	p.playerOrder = participants
+
+	p.seed = p.server.newGameSeed()
+	p.random = rand.New(rand.NewSource(p.seed))
+	log.Printf("starting %s in party %s(%d) with seed %d\n", t.Name, p.name, p.id, p.seed)
+
+	// Spiele ohne Zuschauer senden ihren Zustand nur an die einzelnen Spieler und nicht an die ganze Party, deshalb
+	// wäre in ihren Aufzeichnungen nichts zu sehen
+	var gameParty game.Party = p
+	if p.server.config.replayDir != "" && t.SupportsSpectators {
+		p.recorder = newReplayRecorder(p, t, options)
+		gameParty = recordingParty{party: p, recorder: p.recorder}
+	}
+
+	g := t.Creator(gameParty, options)
 	if g == nil {
+		p.participants = nil
+		p.playerOrder = nil
+		p.recorder = nil
 		return errors.New("cannot create the game")
 	}
 
 	p.currentGame = g
+	p.currentType = t
+	p.currentOptions = options
+	p.rematch = nil
+	p.resetPause()
+
+	for _, participant := range participants {
+		if participant.bot != nil {
+			participant.bot.start(participant, t, options, rand.New(rand.NewSource(p.random.Int63())))
+		}
+	}
+
+	p.BroadcastPacket(p.gameStartedPacket())
+
+	// Das Spiel wird erst danach gestartet, damit die Clients die Packets, die das Spiel zu Beginn sendet,
+	// bereits verarbeiten können
 	p.currentGame.HandleGameStarted()
 
-	gameStarted, err := json.Marshal(protocol.GameStartedPacket{
-		PacketName: protocol.GameStartedPacketName,
-		GameType:   t.Name,
-	})
-	if err != nil {
-		panic(err)
-	}
-	p.BroadcastPacket(gameStarted)
+	p.server.notifyPartyUpdated(p)
 
 	return nil
 }
@@ -125,25 +503,68 @@ func (p *party) handleEndGamePacket() error {
 		return errors.New("there is no game currently running")
 	}
 
-	p.EndGame()
+	p.EndGame(game.AbortedResult)
 	return nil
 }
 
-func (p *party) EndGame() {
+func (p *party) EndGame(result game.Result) {
 	if p.currentGame == nil {
 		return
 	}
 
 	p.currentGame.HandleGameEnded()
+	for _, participant := range p.participants {
+		if participant.bot != nil {
+			participant.bot.stop()
+		}
+	}
+
+	// Bei der Playlist wird das nächste Spiel automatisch gestartet
+	var players []*player
+	for _, participant := range p.PlayerOrder() {
+		players = append(players, participant.(*player))
+	}
+	if !p.playlist.running && len(players) == len(p.playerOrder) && len(players) != 0 {
+		p.rematch = newRematch(p.currentType, p.currentOptions, players)
+	}
+
 	p.currentGame = nil
+	p.currentType = game.Type{}
+	p.currentOptions = nil
+	p.participants = nil
+	p.playerOrder = nil
+	p.resetPause()
+	seed := p.seed
+	p.seed = 0
+	p.random = nil
+
+	if p.recorder != nil {
+		path, err := p.recorder.save(p.server.config.replayDir, p.id, result)
+		if err != nil {
+			log.Println(fmt.Errorf("failed to save the replay: %w", err))
+		} else {
+			log.Printf("saved replay: %s\n", path)
+		}
+		p.recorder = nil
+	}
 
 	gameEnded, err := json.Marshal(protocol.GameEndedPacket{
 		PacketName: protocol.GameEndedPacketName,
+		Result:     result.ToData(),
+		Seed:       seed,
 	})
 	if err != nil {
 		panic(err)
 	}
 	p.BroadcastPacket(gameEnded)
+
+	if p.rematch != nil {
+		p.broadcastRematchStatus()
+	}
+
+	p.server.notifyPartyUpdated(p)
+
+	p.playlist.handleGameEnded(result)
 }
 
 func (p *party) handleGamePacket(sender *player, data []byte) error {
@@ -151,6 +572,14 @@ func (p *party) handleGamePacket(sender *player, data []byte) error {
 		return errors.New("there is no game running")
 	}
 
+	if _, participating := p.participants[sender.id]; !participating {
+		return errors.New("player is not participating in the game")
+	}
+
+	if p.paused {
+		return errors.New("the game is paused")
+	}
+
 	err := p.currentGame.HandlePacket(sender, data)
 	if err != nil {
 		return fmt.Errorf("the game failed to handle the packet: %w", err)
@@ -159,10 +588,36 @@ func (p *party) handleGamePacket(sender *player, data []byte) error {
 	return nil
 }
 
+// tickBots sendet die Züge der Bots an das Spiel.
+func (p *party) tickBots() {
+	for _, participant := range p.participants {
+		if participant.bot == nil {
+			continue
+		}
+
+		for _, data := range participant.bot.tick() {
+			if p.currentGame == nil {
+				return
+			}
+
+			err := p.handleGamePacket(participant, data)
+			if err != nil {
+				log.Println(fmt.Errorf("failed to handle packet from bot %s(%d): %w", participant.name, participant.id, err))
+			}
+		}
+	}
+}
+
 func (p *party) tick() {
-	if p.currentGame != nil {
+	if p.currentGame != nil && !p.paused {
+		p.tickBots()
+	}
+
+	if p.currentGame != nil && !p.paused {
 		p.currentGame.Tick()
 	}
+
+	p.playlist.tick()
 }
 
 func (p *party) Id() int32 {
@@ -174,13 +629,32 @@ func (p *party) Name() string {
 }
 
 func (p *party) Players() map[int32]game.Player {
-	players := make(map[int32]game.Player, len(p.players))
-	for id, player := range p.players {
+	players := make(map[int32]game.Player, len(p.participants))
+	for id, player := range p.participants {
 		players[id] = player
 	}
 	return players
 }
 
+func (p *party) PlayerOrder() []game.Player {
+	players := make([]game.Player, 0, len(p.playerOrder))
+	for _, player := range p.playerOrder {
+		// Spieler, die das Spiel verlassen haben, sind nicht mehr enthalten
+		if _, participating := p.participants[player.id]; participating {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func (p *party) Rand() *rand.Rand {
+	return p.random
+}
+
+func (p *party) Teams() (teams map[int32][]game.Player, ok bool) {
+	return game.GroupByTeam(p.Players())
+}
+
 type parties map[int32]*party
 
 func (p parties) byPlayer(target *player) *party {
@@ -196,9 +670,7 @@ func (p parties) byPlayer(target *player) *party {
 func (p parties) toListPartiesData() []protocol.PartyData {
 	parties := make([]protocol.PartyData, 0, len(p))
 	for _, party := range p {
-		if party.currentGame == nil {
-			parties = append(parties, party.toData())
-		}
+		parties = append(parties, party.toData())
 	}
 	return parties
 }
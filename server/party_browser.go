@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+)
+
+// Spieler, die sich im Party Browser befinden, werden über alle Änderungen an den Partys informiert.
+
+func (s *server) subscribePartyBrowser(player *player) {
+	s.partyBrowsers[player.id] = player
+
+	listParties, err := json.Marshal(protocol.ListPartiesPacket{
+		PacketName: protocol.ListPartiesPacketName,
+		Parties:    s.parties.toListPartiesData(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	player.SendPacket(listParties)
+}
+
+func (s *server) unsubscribePartyBrowser(player *player) {
+	delete(s.partyBrowsers, player.id)
+}
+
+func (s *server) broadcastToPartyBrowsers(data []byte) {
+	for _, browser := range s.partyBrowsers {
+		browser.SendPacket(data)
+	}
+}
+
+func (s *server) notifyPartyCreated(party *party) {
+	partyCreated, err := json.Marshal(protocol.PartyCreatedPacket{
+		PacketName: protocol.PartyCreatedPacketName,
+		Party:      party.toData(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	s.broadcastToPartyBrowsers(partyCreated)
+}
+
+func (s *server) notifyPartyUpdated(party *party) {
+	partyUpdated, err := json.Marshal(protocol.PartyUpdatedPacket{
+		PacketName: protocol.PartyUpdatedPacketName,
+		Party:      party.toData(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	s.broadcastToPartyBrowsers(partyUpdated)
+}
+
+func (s *server) notifyPartyRemoved(party *party) {
+	partyRemoved, err := json.Marshal(protocol.PartyRemovedPacket{
+		PacketName: protocol.PartyRemovedPacketName,
+		Id:         party.id,
+	})
+	if err != nil {
+		panic(err)
+	}
+	s.broadcastToPartyBrowsers(partyRemoved)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// pauseVotesNeeded gibt zurück, wie viele Spieler dafür stimmen müssen, das Spiel zu pausieren oder fortzusetzen.
+// Bots stimmen nicht ab.
+func (p *party) pauseVotesNeeded() int {
+	var humans int
+	for _, participant := range p.participants {
+		if participant.bot == nil {
+			humans++
+		}
+	}
+	return humans/2 + 1
+}
+
+func (p *party) pauseStatusPacket() []byte {
+	votes := make([]int32, 0, len(p.pauseVotes))
+	for id := range p.pauseVotes {
+		votes = append(votes, id)
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i] < votes[j] })
+
+	pauseStatus, err := json.Marshal(protocol.PauseStatusPacket{
+		PacketName:  protocol.PauseStatusPacketName,
+		Paused:      p.paused,
+		PausedBy:    p.pausedBy,
+		Votes:       votes,
+		VotesNeeded: int32(p.pauseVotesNeeded()),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return pauseStatus
+}
+
+func (p *party) handlePauseGamePacket(sender *player, packet protocol.PauseGamePacket) error {
+	if p.currentGame == nil {
+		return errors.New("there is no game running")
+	}
+
+	if packet.Paused == p.paused {
+		return errors.New("the game is already in this state")
+	}
+
+	if p.isHost(sender) {
+		p.setPaused(packet.Paused, sender.id)
+		return nil
+	}
+
+	if _, participating := p.participants[sender.id]; !participating {
+		return errors.New("player is not participating in the game")
+	}
+
+	p.pauseVotes[sender.id] = struct{}{}
+	if len(p.pauseVotes) >= p.pauseVotesNeeded() {
+		p.setPaused(packet.Paused, 0)
+		return nil
+	}
+
+	p.BroadcastPacket(p.pauseStatusPacket())
+	return nil
+}
+
+// setPaused pausiert das Spiel oder setzt es fort. by ist der Spieler, der das veranlasst hat, oder 0 bei einer
+// Abstimmung.
+func (p *party) setPaused(paused bool, by int32) {
+	p.paused = paused
+	p.pausedBy = 0
+	if paused {
+		p.pausedBy = by
+	}
+	p.pauseVotes = map[int32]struct{}{}
+
+	if p.recorder != nil {
+		if paused {
+			p.recorder.pause()
+		} else {
+			p.recorder.resume()
+		}
+	}
+
+	// Die Clients erfahren zuerst von der Pause, damit sie die Packets, die das Spiel beim Fortsetzen sendet,
+	// bereits im fortgesetzten Spiel verarbeiten
+	p.BroadcastPacket(p.pauseStatusPacket())
+
+	if pausable, ok := p.currentGame.(game.Pausable); ok {
+		if paused {
+			pausable.HandlePaused()
+		} else {
+			pausable.HandleResumed()
+		}
+	}
+}
+
+// resetPause wird aufgerufen, wenn ein Spiel beginnt oder endet.
+func (p *party) resetPause() {
+	p.paused = false
+	p.pausedBy = 0
+	p.pauseVotes = map[int32]struct{}{}
+}
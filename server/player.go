@@ -21,11 +21,14 @@ type player struct {
 	conn           net.Conn
 	name           string
 	id             int32
+	sessionToken   string // Nur bei Spielern, die mit dem Server verbunden sind
+	team           int32  // Das Team des Spielers in seiner aktuellen Party
 	send           chan []byte
 	receive        chan []byte
 	disconnected   chan struct{} // um die goroutine forwardMessagesFromPlayer zu schließen, nachdem die Verbindung getrennt wurde
 	disconnectOnce sync.Once
 	server         *server
+	bot            *bot // nil bei Spielern, die mit dem Server verbunden sind
 }
 
 var _ game.Player = (*player)(nil)
@@ -34,7 +37,8 @@ func newPlayerForNewConnection(s *server, conn net.Conn) *player {
 	return &player{
 		conn:         conn,
 		name:         randomPlayerNames[rand.Intn(len(randomPlayerNames))],
-		id:           rand.Int31(),
+		id:           s.playerIds.allocate(),
+		sessionToken: newSessionToken(),
 		send:         make(chan []byte, 100),
 		receive:      make(chan []byte, 100),
 		disconnected: make(chan struct{}, 1),
@@ -46,6 +50,8 @@ func (p *player) toData() protocol.PlayerData {
 	return protocol.PlayerData{
 		Name: p.name,
 		Id:   p.id,
+		Team: p.team,
+		Bot:  p.bot != nil,
 	}
 }
 
@@ -113,6 +119,11 @@ func (p *player) disconnect() {
 }
 
 func (p *player) SendPacket(data []byte) {
+	if p.bot != nil {
+		p.bot.handlePacket(p, data)
+		return
+	}
+
 	select {
 	case p.send <- data:
 		return
@@ -130,4 +141,8 @@ func (p *player) Name() string {
 	return p.name
 }
 
+func (p *player) Team() int32 {
+	return p.team
+}
+
 type players map[int32]*player
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+const (
+	maxPlaylistEntries        = 20
+	maxPlaylistRounds         = 10
+	playlistTicksBetweenGames = 3000 / protocol.TickSpeed // Die Pause zwischen zwei Spielen der Playlist
+)
+
+// playlistMatch ist ein einzelnes Spiel, das im Rahmen der Playlist gespielt wird.
+type playlistMatch struct {
+	gameType game.Type
+	options  protocol.OptionValues
+	players  []int32 // Die IDs der Spieler, die am Spiel teilnehmen. nil bedeutet, dass alle Spieler der Party teilnehmen.
+}
+
+type playlist struct {
+	party               *party
+	entries             []protocol.PlaylistEntryData
+	running             bool
+	matches             []playlistMatch // Die Spiele, die noch gespielt werden müssen
+	ticksUntilNextMatch int
+	points              map[int32]int32
+}
+
+func newPlaylist(party *party) *playlist {
+	return &playlist{
+		party: party,
+	}
+}
+
+func (p *playlist) toData() protocol.PlaylistData {
+	entries := make([]protocol.PlaylistEntryData, len(p.entries))
+	copy(entries, p.entries)
+
+	return protocol.PlaylistData{
+		Entries: entries,
+		Running: p.running,
+	}
+}
+
+func (p *playlist) broadcastChanged() {
+	playlistChanged, err := json.Marshal(protocol.PlaylistChangedPacket{
+		PacketName: protocol.PlaylistChangedPacketName,
+		Playlist:   p.toData(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.party.BroadcastPacket(playlistChanged)
+
+	p.party.server.notifyPartyUpdated(p.party)
+}
+
+func (p *playlist) addEntry(packet protocol.AddPlaylistEntryPacket) error {
+	if p.running {
+		return errors.New("cannot edit the playlist while it is running")
+	}
+
+	if len(p.entries) >= maxPlaylistEntries {
+		return errors.New("the playlist is full")
+	}
+
+	t, ok := gameTypeByName(packet.GameType)
+	if !ok {
+		return fmt.Errorf("cannot find game type %s", packet.GameType)
+	}
+
+	options, err := t.ValidateOptions(packet.Options)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	if packet.Rounds < 1 || packet.Rounds > maxPlaylistRounds {
+		return fmt.Errorf("invalid number of rounds: %d", packet.Rounds)
+	}
+
+	p.entries = append(p.entries, protocol.PlaylistEntryData{
+		GameType: packet.GameType,
+		Rounds:   packet.Rounds,
+		Options:  options,
+	})
+	p.broadcastChanged()
+
+	return nil
+}
+
+func (p *playlist) removeEntry(packet protocol.RemovePlaylistEntryPacket) error {
+	if p.running {
+		return errors.New("cannot edit the playlist while it is running")
+	}
+
+	if packet.Index < 0 || int(packet.Index) >= len(p.entries) {
+		return fmt.Errorf("invalid playlist index: %d", packet.Index)
+	}
+
+	p.entries = append(p.entries[:packet.Index], p.entries[packet.Index+1:]...)
+	p.broadcastChanged()
+
+	return nil
+}
+
+// roundRobinPairings gibt alle Paarungen der Spieler zurück, sodass jeder Spieler genau einmal gegen jeden anderen spielt.
+// In ungeraden Runden wird die Reihenfolge innerhalb der Paarungen getauscht, damit jeder Spieler auch einmal beginnt.
+func roundRobinPairings(players []*player, round int) [][]int32 {
+	var pairings [][]int32
+	for i := 0; i < len(players); i++ {
+		for j := i + 1; j < len(players); j++ {
+			if round%2 == 0 {
+				pairings = append(pairings, []int32{players[i].id, players[j].id})
+			} else {
+				pairings = append(pairings, []int32{players[j].id, players[i].id})
+			}
+		}
+	}
+	return pairings
+}
+
+func (p *playlist) createMatches() ([]playlistMatch, error) {
+	players := p.party.playersSorted()
+
+	var matches []playlistMatch
+	for _, entry := range p.entries {
+		t, ok := gameTypeByName(entry.GameType)
+		if !ok {
+			return nil, fmt.Errorf("cannot find game type %s", entry.GameType)
+		}
+
+		if len(players) < t.MinPlayers {
+			return nil, fmt.Errorf("not enough players for %s", t.Name)
+		}
+
+		for round := 0; round < int(entry.Rounds); round++ {
+			switch {
+			case t.PlaylistPairings && len(players) > 2:
+				for _, pairing := range roundRobinPairings(players, round) {
+					matches = append(matches, playlistMatch{gameType: t, options: entry.Options, players: pairing})
+				}
+			case t.MaxPlayers == 0 || len(players) <= t.MaxPlayers:
+				matches = append(matches, playlistMatch{gameType: t, options: entry.Options})
+			default:
+				return nil, fmt.Errorf("too many players for %s", t.Name)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+func (p *playlist) start() error {
+	if p.running {
+		return errors.New("the playlist is already running")
+	}
+
+	if len(p.entries) == 0 {
+		return errors.New("the playlist is empty")
+	}
+
+	if p.party.currentGame != nil {
+		return errors.New("a game is running")
+	}
+
+	matches, err := p.createMatches()
+	if err != nil {
+		return fmt.Errorf("failed to create the matches: %w", err)
+	}
+
+	p.running = true
+	p.matches = matches
+	p.ticksUntilNextMatch = 0
+	p.points = make(map[int32]int32, len(p.party.players))
+	for id := range p.party.players {
+		p.points[id] = 0
+	}
+	p.broadcastChanged()
+
+	return nil
+}
+
+func (p *playlist) stop() error {
+	if !p.running {
+		return errors.New("the playlist is not running")
+	}
+
+	p.running = false
+	p.matches = nil
+	p.points = nil
+	p.broadcastChanged()
+
+	return nil
+}
+
+// handleGameEnded vergibt die Punkte für ein beendetes Spiel.
+// Jeder Spieler erhält so viele Punkte, wie Spieler hinter ihm platziert sind.
+func (p *playlist) handleGameEnded(result game.Result) {
+	if !p.running {
+		return
+	}
+
+	playersBehind := 0
+	for _, place := range result.Ranking {
+		playersBehind += len(place)
+	}
+	for _, place := range result.Ranking {
+		playersBehind -= len(place)
+		for _, id := range place {
+			p.points[id] += int32(playersBehind)
+		}
+	}
+
+	p.ticksUntilNextMatch = playlistTicksBetweenGames
+}
+
+func (p *playlist) tick() {
+	if !p.running || p.party.currentGame != nil {
+		return
+	}
+
+	p.ticksUntilNextMatch--
+	if p.ticksUntilNextMatch > 0 {
+		return
+	}
+
+	p.startNextMatch()
+}
+
+func (p *playlist) startNextMatch() {
+	for len(p.matches) != 0 {
+		match := p.matches[0]
+		p.matches = p.matches[1:]
+
+		participants, ok := p.matchParticipants(match)
+		if !ok {
+			continue
+		}
+
+		err := p.party.startGame(match.gameType, participants, match.options)
+		if err != nil {
+			log.Println(fmt.Errorf("failed to start the next game of the playlist: %w", err))
+			continue
+		}
+
+		return
+	}
+
+	p.finish()
+}
+
+// matchParticipants sucht die Spieler, die am Spiel teilnehmen. Wenn einer der Spieler die Party verlassen hat, kann
+// das Spiel nicht stattfinden.
+func (p *playlist) matchParticipants(match playlistMatch) (participants []*player, ok bool) {
+	if match.players == nil {
+		return p.party.playersSorted(), true
+	}
+
+	participants = make([]*player, len(match.players))
+	for i, id := range match.players {
+		participant, ok := p.party.players[id]
+		if !ok {
+			return nil, false
+		}
+		participants[i] = participant
+	}
+	return participants, true
+}
+
+func (p *playlist) standings() []protocol.StandingData {
+	standings := make([]protocol.StandingData, 0, len(p.party.players))
+	for _, player := range p.party.playersSorted() {
+		standings = append(standings, protocol.StandingData{
+			Player: player.toData(),
+			Points: p.points[player.id],
+		})
+	}
+	sort.SliceStable(standings, func(i, j int) bool { return standings[i].Points > standings[j].Points })
+	return standings
+}
+
+func (p *playlist) finish() {
+	standings, err := json.Marshal(protocol.StandingsPacket{
+		PacketName: protocol.StandingsPacketName,
+		Standings:  p.standings(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.party.BroadcastPacket(standings)
+
+	p.running = false
+	p.matches = nil
+	p.points = nil
+	p.broadcastChanged()
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// rematch ist die Abstimmung darüber, das letzte Spiel der Party noch einmal zu spielen.
+type rematch struct {
+	gameType game.Type
+	options  protocol.OptionValues
+	players  []*player // In der Reihenfolge des letzten Spieles
+	votes    map[int32]struct{}
+}
+
+func newRematch(t game.Type, options protocol.OptionValues, players []*player) *rematch {
+	votes := make(map[int32]struct{}, len(players))
+	for _, player := range players {
+		if player.bot != nil {
+			votes[player.id] = struct{}{}
+		}
+	}
+
+	return &rematch{
+		gameType: t,
+		options:  options,
+		players:  players,
+		votes:    votes,
+	}
+}
+
+func (r *rematch) hasPlayer(target *player) bool {
+	for _, player := range r.players {
+		if player == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rematch) complete() bool {
+	return len(r.votes) == len(r.players)
+}
+
+// nextOrder gibt die Spieler für die Revanche zurück. Der Spieler, der zuletzt begonnen hat, ist jetzt als letztes
+// an der Reihe, damit bei zwei Spielern der andere Spieler beginnt.
+func (r *rematch) nextOrder() []*player {
+	order := make([]*player, 0, len(r.players))
+	order = append(order, r.players[1:]...)
+	order = append(order, r.players[0])
+	return order
+}
+
+func (r *rematch) toPacket() protocol.RematchStatusPacket {
+	players := make([]int32, len(r.players))
+	for i, player := range r.players {
+		players[i] = player.id
+	}
+
+	votes := make([]int32, 0, len(r.votes))
+	for id := range r.votes {
+		votes = append(votes, id)
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i] < votes[j] })
+
+	return protocol.RematchStatusPacket{
+		PacketName: protocol.RematchStatusPacketName,
+		Available:  true,
+		GameType:   r.gameType.Name,
+		Players:    players,
+		Votes:      votes,
+	}
+}
+
+func (p *party) broadcastRematchStatus() {
+	packet := protocol.RematchStatusPacket{
+		PacketName: protocol.RematchStatusPacketName,
+	}
+	if p.rematch != nil {
+		packet = p.rematch.toPacket()
+	}
+
+	rematchStatus, err := json.Marshal(packet)
+	if err != nil {
+		panic(err)
+	}
+	p.BroadcastPacket(rematchStatus)
+}
+
+// cancelRematch beendet die Abstimmung, zum Beispiel weil ein Spieler des letzten Spieles die Party verlassen hat.
+func (p *party) cancelRematch() {
+	if p.rematch == nil {
+		return
+	}
+
+	p.rematch = nil
+	p.broadcastRematchStatus()
+}
+
+func (p *party) handleRematchPacket(sender *player) error {
+	if p.rematch == nil {
+		return errors.New("there is no rematch available")
+	}
+
+	if p.currentGame != nil {
+		return errors.New("a game is running")
+	}
+
+	if !p.rematch.hasPlayer(sender) {
+		return errors.New("player did not participate in the last game")
+	}
+
+	if _, voted := p.rematch.votes[sender.id]; voted {
+		return errors.New("player has already voted for a rematch")
+	}
+
+	p.rematch.votes[sender.id] = struct{}{}
+	p.broadcastRematchStatus()
+
+	if !p.rematch.complete() {
+		return nil
+	}
+
+	rematch := p.rematch
+	err := p.startGame(rematch.gameType, rematch.nextOrder(), rematch.options)
+	if err != nil {
+		log.Println(fmt.Errorf("failed to start the rematch: %w", err))
+		p.cancelRematch()
+	}
+
+	return nil
+}
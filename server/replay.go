@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// replayRecorder zeichnet die Packets auf, die ein Spiel an die Party sendet. Die Zeit, in der das Spiel pausiert war,
+// wird nicht mitgezählt, damit die Wiedergabe nicht während der Pausen stehen bleibt.
+type replayRecorder struct {
+	replay    protocol.Replay
+	pausedAt  time.Time // Null, solange das Spiel nicht pausiert ist
+	pausedFor time.Duration
+}
+
+func newReplayRecorder(p *party, t game.Type, options protocol.OptionValues) *replayRecorder {
+	players := make([]protocol.PlayerData, 0, len(p.players))
+	for _, player := range p.playersSorted() {
+		players = append(players, player.toData())
+	}
+
+	participants := make([]int32, 0, len(p.participants))
+	for id := range p.participants {
+		participants = append(participants, id)
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i] < participants[j] })
+
+	return &replayRecorder{
+		replay: protocol.Replay{
+			GameType:     t.Name,
+			GameVersion:  int32(t.Version),
+			Options:      options,
+			Seed:         p.seed,
+			PartyName:    p.name,
+			Players:      players,
+			Participants: participants,
+			StartedAt:    time.Now(),
+		},
+	}
+}
+
+func (r *replayRecorder) pause() {
+	if r.pausedAt.IsZero() {
+		r.pausedAt = time.Now()
+	}
+}
+
+func (r *replayRecorder) resume() {
+	if !r.pausedAt.IsZero() {
+		r.pausedFor += time.Since(r.pausedAt)
+		r.pausedAt = time.Time{}
+	}
+}
+
+// elapsed gibt zurück, wie lange das Spiel seit dem Start ohne die Pausen gelaufen ist.
+func (r *replayRecorder) elapsed() time.Duration {
+	elapsed := time.Since(r.replay.StartedAt) - r.pausedFor
+	if !r.pausedAt.IsZero() {
+		elapsed -= time.Since(r.pausedAt)
+	}
+	return elapsed
+}
+
+func (r *replayRecorder) record(data []byte) {
+	r.replay.Packets = append(r.replay.Packets, protocol.ReplayPacket{
+		Time: r.elapsed().Milliseconds(),
+		Data: data,
+	})
+}
+
+// save speichert die Aufzeichnung im Ordner dir.
+func (r *replayRecorder) save(dir string, partyId int32, result game.Result) (path string, err error) {
+	r.replay.Duration = r.elapsed().Milliseconds()
+	r.replay.Result = result.ToData()
+
+	data, err := json.Marshal(r.replay)
+	if err != nil {
+		panic(err)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the replay directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s-%s-%d%s", r.replay.StartedAt.Format("2006-01-02-15-04-05"), r.replay.GameType, partyId,
+		protocol.ReplayFileExtension)
+	path = filepath.Join(dir, fileName)
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write the replay file: %w", err)
+	}
+
+	return path, nil
+}
+
+// recordingParty wird an Spiele übergeben, die aufgezeichnet werden, und zeichnet alle Packets auf, die das Spiel an
+// die Party sendet.
+type recordingParty struct {
+	*party
+	recorder *replayRecorder
+}
+
+var _ game.Party = recordingParty{}
+
+func (r recordingParty) BroadcastPacket(data []byte) {
+	r.recorder.record(data)
+	r.party.BroadcastPacket(data)
+}
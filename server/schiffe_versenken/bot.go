@@ -0,0 +1,179 @@
+package schiffe_versenken
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	shared "github.com/Lama06/Oinky-Party/schiffe_versenken"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+const (
+	botMoveDelay        = 10  // Wie viele Ticks der Bot wartet, bevor er schießt
+	randomShipsAttempts = 100 // Wie oft randomShips versucht, die Flotte zufällig aufzustellen
+)
+
+// randomShips stellt die Flotte zufällig auf. Die Schiffe werden, die längsten zuerst, nacheinander auf zufällige
+// freie Felder gelegt. Ist für ein Schiff kein Platz mehr, wird von vorne begonnen. Gelingt das zu oft nicht, wird die
+// Aufstellung von shared.Rules.FirstFitShips verwendet, die es bei gültigen Regeln immer gibt.
+func randomShips(rules shared.Rules, random *rand.Rand) shared.Ships {
+	for attempt := 0; attempt < randomShipsAttempts; attempt++ {
+		if ships, ok := tryRandomShips(rules, random); ok {
+			return ships
+		}
+	}
+	ships, _ := rules.FirstFitShips()
+	return ships
+}
+
+func tryRandomShips(rules shared.Rules, random *rand.Rand) (shared.Ships, bool) {
+	var ships shared.Ships
+	blocked := make(map[shared.Position]struct{})
+	for _, length := range rules.Fleet.Lengths() {
+		var candidates []shared.Ship
+		for _, horizontal := range []bool{true, false} {
+			for x := 0; x < rules.Width; x++ {
+				for y := 0; y < rules.Height; y++ {
+					ship := shared.NewShip(shared.Position{X: x, Y: y}, length, horizontal)
+					if ship.Valid(rules) && !overlaps(ship, blocked) {
+						candidates = append(candidates, ship)
+					}
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, false
+		}
+
+		ship := candidates[random.Intn(len(candidates))]
+		ships = append(ships, ship)
+		for pos := range ship.BlockedFields(rules) {
+			blocked[pos] = struct{}{}
+		}
+	}
+	return ships, true
+}
+
+func overlaps(ship shared.Ship, fields map[shared.Position]struct{}) bool {
+	for _, pos := range ship {
+		if _, ok := fields[pos]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bot stellt seine Schiffe zufällig auf und wählt seine Ziele mit targeting.
+type bot struct {
+	self           game.Player
+	send           func(data []byte)
+	rules          *shared.Rules // nil, bis das shared.RulesPacket empfangen wurde
+	targeting      *targeting
+	difficulty     int32
+	hasSetupShips  bool
+	myTurn         bool
+	ticksUntilMove int
+	random         *rand.Rand
+}
+
+var _ game.Bot = (*bot)(nil)
+
+func createBot(self game.Player, options protocol.OptionValues, random *rand.Rand, send func(data []byte)) game.Bot {
+	return &bot{
+		self:           self,
+		send:           send,
+		difficulty:     shared.Difficulty(options),
+		ticksUntilMove: botMoveDelay,
+		random:         random,
+	}
+}
+
+var _ game.BotCreator = createBot
+
+func (b *bot) HandlePacket(data []byte) error {
+	packetName, err := protocol.GetPacketName(data)
+	if err != nil {
+		return fmt.Errorf("failed to get packet name: %w", err)
+	}
+
+	switch packetName {
+	case shared.RulesPacketName:
+		var rules shared.RulesPacket
+		err := json.Unmarshal(data, &rules)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if !rules.Rules.Valid() {
+			return fmt.Errorf("invalid rules: %+v", rules.Rules)
+		}
+		b.rules = &rules.Rules
+		b.targeting = newTargeting(rules.Rules)
+	case protocol.TurnChangedPacketName:
+		var turnChanged protocol.TurnChangedPacket
+		err := json.Unmarshal(data, &turnChanged)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		b.myTurn = turnChanged.Player == b.self.Id()
+		b.ticksUntilMove = botMoveDelay
+	case shared.FireResultPacketName:
+		var fireResult shared.FireResultPacket
+		err := json.Unmarshal(data, &fireResult)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+
+		if b.targeting != nil {
+			b.targeting.handleFireResult(fireResult)
+		}
+	}
+
+	return nil
+}
+
+func (b *bot) Tick() {
+	if b.rules == nil {
+		return
+	}
+
+	if !b.hasSetupShips {
+		b.setupShips()
+		return
+	}
+
+	if !b.myTurn {
+		return
+	}
+
+	b.ticksUntilMove--
+	if b.ticksUntilMove > 0 {
+		return
+	}
+	b.ticksUntilMove = botMoveDelay
+
+	fire, err := json.Marshal(shared.FirePacket{
+		PacketName: shared.FirePacketName,
+		Position:   b.targeting.choose(b.difficulty, b.random),
+	})
+	if err != nil {
+		panic(err)
+	}
+	b.send(fire)
+}
+
+func (b *bot) setupShips() {
+	b.hasSetupShips = true
+
+	setupShips, err := json.Marshal(shared.SetupShipsPacket{
+		PacketName: shared.SetupShipsPacketName,
+		Ships:      randomShips(*b.rules, b.random),
+	})
+	if err != nil {
+		panic(err)
+	}
+	b.send(setupShips)
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/Lama06/Oinky-Party/protocol"
 	shared "github.com/Lama06/Oinky-Party/schiffe_versenken"
 	"github.com/Lama06/Oinky-Party/server/game"
+	"github.com/Lama06/Oinky-Party/server/turns"
 )
 
 type cell byte
@@ -15,24 +18,36 @@ type cell byte
 const (
 	emptyCell cell = iota
 	shipCell
+	missCell // Auf dieses Feld wurde bereits ohne Treffer geschossen
+	hitCell  // Auf dieses Feld wurde bereits geschossen und ein Schiff getroffen
 )
 
-type board [shared.BoardWidth][shared.BoardHeight]cell
+// board ist das Spielfeld eines Spielers. Die Größe wird beim Starten des Spieles mit den Einstellungen festgelegt.
+type board struct {
+	rules shared.Rules
+	cells [][]cell // cells[x][y]
+}
 
-func newBoardFromShips(ships shared.Ships) *board {
-	result := &board{}
+func newBoardFromShips(rules shared.Rules, ships shared.Ships) *board {
+	cells := make([][]cell, rules.Width)
+	for x := range cells {
+		cells[x] = make([]cell, rules.Height)
+	}
 	for _, ship := range ships {
 		for _, pos := range ship {
-			result[pos.X][pos.Y] = shipCell
+			cells[pos.X][pos.Y] = shipCell
 		}
 	}
-	return result
+	return &board{
+		rules: rules,
+		cells: cells,
+	}
 }
 
 func (b *board) isEmpty() bool {
-	for x := 0; x < shared.BoardWidth; x++ {
-		for y := 0; y < shared.BoardHeight; y++ {
-			if b[x][y] == shipCell {
+	for x := 0; x < b.rules.Width; x++ {
+		for y := 0; y < b.rules.Height; y++ {
+			if b.cells[x][y] == shipCell {
 				return false
 			}
 		}
@@ -42,14 +57,60 @@ func (b *board) isEmpty() bool {
 }
 
 func (b *board) fire(pos shared.Position) (hit bool) {
-	hit = b[pos.X][pos.Y] == shipCell
-	b[pos.X][pos.Y] = emptyCell
-	return
+	switch b.cells[pos.X][pos.Y] {
+	case shipCell:
+		b.cells[pos.X][pos.Y] = hitCell
+		return true
+	case emptyCell:
+		b.cells[pos.X][pos.Y] = missCell
+	}
+	return false
+}
+
+// isSunk gibt an, ob alle Felder des Schiffes getroffen wurden.
+func (b *board) isSunk(ship shared.Ship) bool {
+	for _, pos := range ship {
+		if b.cells[pos.X][pos.Y] != hitCell {
+			return false
+		}
+	}
+	return len(ship) != 0
+}
+
+func (b *board) fired(pos shared.Position) bool {
+	return b.cells[pos.X][pos.Y] == missCell || b.cells[pos.X][pos.Y] == hitCell
+}
+
+// shots gibt alle Schüsse zurück, die auf das Spielfeld abgegeben wurden.
+func (b *board) shots() []shared.Shot {
+	var shots []shared.Shot
+	for x := 0; x < b.rules.Width; x++ {
+		for y := 0; y < b.rules.Height; y++ {
+			pos := shared.Position{X: x, Y: y}
+			if b.fired(pos) {
+				shots = append(shots, shared.Shot{Position: pos, Hit: b.cells[x][y] == hitCell})
+			}
+		}
+	}
+	return shots
+}
+
+func (b *board) randomTarget(random *rand.Rand) shared.Position {
+	var targets []shared.Position
+	for x := 0; x < b.rules.Width; x++ {
+		for y := 0; y < b.rules.Height; y++ {
+			if !b.fired(shared.Position{X: x, Y: y}) {
+				targets = append(targets, shared.Position{X: x, Y: y})
+			}
+		}
+	}
+	return targets[random.Intn(len(targets))]
 }
 
 type player struct {
 	handle        game.Player
 	hasSetupShips bool
+	ships         shared.Ships
 	board         *board
 }
 
@@ -59,45 +120,136 @@ func newPlayer(handle game.Player) *player {
 	}
 }
 
+// shipAt gibt das Schiff zurück, das auf dem Feld liegt, oder nil, wenn dort kein Schiff liegt.
+func (p *player) shipAt(pos shared.Position) shared.Ship {
+	for _, ship := range p.ships {
+		for _, field := range ship {
+			if field == pos {
+				return ship
+			}
+		}
+	}
+	return nil
+}
+
+// sunkShips gibt die Schiffe des Spielers zurück, die bereits versenkt wurden.
+func (p *player) sunkShips() shared.Ships {
+	var sunk shared.Ships
+	for _, ship := range p.ships {
+		if p.board.isSunk(ship) {
+			sunk = append(sunk, ship)
+		}
+	}
+	return sunk
+}
+
 type impl struct {
-	party         game.Party
-	gameStarted   bool
-	player1       *player
-	player2       *player
-	currentPlayer *player
+	party       game.Party
+	options     protocol.OptionValues
+	rules       shared.Rules
+	gameStarted bool
+	player1     *player
+	player2     *player
+	turns       *turns.Order // Wird erst erstellt, wenn beide Spieler ihre Schiffe aufgestellt haben
+	// Wie viele Ticks die Spieler noch Zeit haben, ihre Schiffe aufzustellen, -1, wenn die Zeit nicht begrenzt ist
+	setupTicksLeft int
 }
 
 var _ game.Game = (*impl)(nil)
-
-func create(party game.Party) game.Game {
-	if len(party.Players()) != 2 {
+var _ game.Snapshotter = (*impl)(nil)
+var _ game.Pausable = (*impl)(nil)
+var _ turns.Handler = (*impl)(nil)
+
+func create(party game.Party, options protocol.OptionValues) game.Game {
+	// Der erste Spieler beginnt
+	players := party.PlayerOrder()
+	if len(players) != 2 {
 		return nil
 	}
 
-	players := make([]game.Player, 0, 2)
-	for _, player := range party.Players() {
-		players = append(players, player)
+	rules := shared.RulesFromOptions(options)
+	if !rules.Valid() {
+		return nil
 	}
 
 	player1 := newPlayer(players[0])
 	player2 := newPlayer(players[1])
 
+	setupTicksLeft := -1
+	if setupTime := shared.SetupTime(options); setupTime != 0 {
+		setupTicksLeft = int(setupTime / (protocol.TickSpeed * time.Millisecond))
+	}
+
 	return &impl{
-		party:         party,
-		player1:       player1,
-		player2:       player2,
-		currentPlayer: player1,
+		party:          party,
+		options:        options,
+		rules:          rules,
+		player1:        player1,
+		player2:        player2,
+		setupTicksLeft: setupTicksLeft,
 	}
 }
 
 var _ game.Creator = create
 
-func (i *impl) HandleGameStarted() {}
+func (i *impl) HandleGameStarted() {
+	rules, err := json.Marshal(shared.RulesPacket{
+		PacketName: shared.RulesPacketName,
+		Rules:      i.rules,
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(rules)
+
+	i.broadcastSetupTime()
+}
+
+// setupTimeLeft gibt zurück, wie viele Millisekunden die Spieler noch Zeit haben, ihre Schiffe aufzustellen.
+// ok ist false, wenn die Zeit nicht begrenzt ist oder das Spiel bereits begonnen hat.
+func (i *impl) setupTimeLeft() (milliseconds int32, ok bool) {
+	if i.gameStarted || i.setupTicksLeft < 0 {
+		return 0, false
+	}
+	return int32(i.setupTicksLeft * protocol.TickSpeed), true
+}
+
+func (i *impl) broadcastSetupTime() {
+	setupTimeLeft, ok := i.setupTimeLeft()
+	if !ok {
+		return
+	}
+
+	setupTime, err := json.Marshal(shared.SetupTimePacket{
+		PacketName:    shared.SetupTimePacketName,
+		SetupTimeLeft: setupTimeLeft,
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(setupTime)
+}
 
 func (i *impl) HandleGameEnded() {}
 
 func (i *impl) HandlePlayerLeft(player game.Player) {
-	i.party.EndGame()
+	leftPlayer := i.getPlayer(player)
+	if leftPlayer == nil {
+		return
+	}
+	i.party.EndGame(i.result(i.getOtherPlayer(leftPlayer)))
+}
+
+// result erstellt das Ergebnis mit dem Gewinner. Dabei werden die Flotten beider Spieler aufgedeckt.
+func (i *impl) result(winner *player) game.Result {
+	result := game.WinnerResult(winner.handle, i.getOtherPlayer(winner).handle)
+	result.Details = shared.ResultDetails{
+		Ships: map[int32]shared.Ships{
+			i.player1.handle.Id(): i.player1.ships,
+			i.player2.handle.Id(): i.player2.ships,
+		},
+	}
+	return result
 }
 
 func (i *impl) HandlePacket(sender game.Player, data []byte) error {
@@ -108,14 +260,6 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 
 	senderPlayer := i.getPlayer(sender)
 
-	var otherPlayer *player
-	switch senderPlayer {
-	case i.player1:
-		otherPlayer = i.player2
-	case i.player2:
-		otherPlayer = i.player1
-	}
-
 	switch packetName {
 	case shared.SetupShipsPacketName:
 		var setupShips shared.SetupShipsPacket
@@ -124,7 +268,7 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 			return fmt.Errorf("failed to unmarshal json: %w", err)
 		}
 
-		if !setupShips.Ships.Valid() {
+		if !setupShips.Ships.Valid(i.rules) {
 			return errors.New("invalid ships")
 		}
 
@@ -132,20 +276,7 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 			return errors.New("player has already setup their ships")
 		}
 
-		senderPlayer.hasSetupShips = true
-		senderPlayer.board = newBoardFromShips(setupShips.Ships)
-
-		if otherPlayer.hasSetupShips {
-			i.gameStarted = true
-
-			gameStarted, err := json.Marshal(shared.GameStartedPacket{
-				PacketName: shared.GameStartedPacketName,
-			})
-			if err != nil {
-				panic(err)
-			}
-			i.party.BroadcastPacket(gameStarted)
-		}
+		i.setupShips(senderPlayer, setupShips.Ships)
 
 		return nil
 	case shared.FirePacketName:
@@ -155,7 +286,7 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 			return fmt.Errorf("failed to unmarshl json: %w", err)
 		}
 
-		if !fire.Position.Valid() {
+		if !fire.Position.Valid(i.rules) {
 			return errors.New("invalid position")
 		}
 
@@ -163,47 +294,185 @@ func (i *impl) HandlePacket(sender game.Player, data []byte) error {
 			return errors.New("game has not started yet")
 		}
 
-		if senderPlayer != i.currentPlayer {
-			return errors.New("its not your turn")
+		err = i.turns.CheckTurn(sender)
+		if err != nil {
+			return err
 		}
 
-		hit := otherPlayer.board.fire(fire.Position)
+		i.fire(senderPlayer, fire.Position)
 
-		if hit && otherPlayer.board.isEmpty() {
-			i.party.EndGame()
-			return nil
-		}
+		return nil
+	default:
+		return fmt.Errorf("unknown packet name: %s", packetName)
+	}
+}
 
-		fireResult, err := json.Marshal(shared.FireResultPacket{
-			PacketName: shared.FireResultPacketName,
-			Position:   fire.Position,
-			Hit:        hit,
-		})
-		if err != nil {
-			panic(err)
+// setupShips stellt die Schiffe des Spielers auf. Sobald beide Spieler fertig sind, beginnt das Spiel.
+func (i *impl) setupShips(p *player, ships shared.Ships) {
+	p.hasSetupShips = true
+	p.ships = ships
+	p.board = newBoardFromShips(i.rules, ships)
+
+	if !i.getOtherPlayer(p).hasSetupShips {
+		return
+	}
+
+	i.gameStarted = true
+
+	gameStarted, err := json.Marshal(shared.GameStartedPacket{
+		PacketName: shared.GameStartedPacketName,
+	})
+	if err != nil {
+		panic(err)
+	}
+	i.party.BroadcastPacket(gameStarted)
+
+	i.turns = turns.NewOrder(i.party, i, turns.ConfigFromOptions(i.options),
+		[]game.Player{i.player1.handle, i.player2.handle})
+	i.turns.Start()
+}
+
+// tickSetup stellt die Schiffe der Spieler, die noch nicht fertig sind, zufällig auf, wenn die Zeit zum Aufstellen
+// abgelaufen ist.
+func (i *impl) tickSetup() {
+	if i.gameStarted || i.setupTicksLeft < 0 {
+		return
+	}
+
+	i.setupTicksLeft--
+	if i.setupTicksLeft > 0 {
+		return
+	}
+
+	for _, p := range []*player{i.player1, i.player2} {
+		if p.hasSetupShips {
+			continue
 		}
-		sender.SendPacket(fireResult)
 
-		opponentFired, err := json.Marshal(shared.OpponentFiredPacket{
-			PacketName: shared.OpponentFiredPacketName,
-			Position:   fire.Position,
+		ships := randomShips(i.rules, i.party.Rand())
+		shipsPlaced, err := json.Marshal(shared.ShipsPlacedPacket{
+			PacketName: shared.ShipsPlacedPacketName,
+			Ships:      ships,
 		})
 		if err != nil {
 			panic(err)
 		}
-		otherPlayer.handle.SendPacket(opponentFired)
+		p.handle.SendPacket(shipsPlaced)
 
-		if !hit {
-			i.currentPlayer = otherPlayer
-		}
+		i.setupShips(p, ships)
+	}
+}
+
+func (i *impl) fire(shooter *player, pos shared.Position) {
+	otherPlayer := i.getOtherPlayer(shooter)
 
+	hit := otherPlayer.board.fire(pos)
+
+	var sunkShip shared.Ship
+	if ship := otherPlayer.shipAt(pos); hit && otherPlayer.board.isSunk(ship) {
+		sunkShip = ship
+	}
+
+	fireResult, err := json.Marshal(shared.FireResultPacket{
+		PacketName: shared.FireResultPacketName,
+		Position:   pos,
+		Hit:        hit,
+		Sunk:       sunkShip != nil,
+		Ship:       sunkShip,
+	})
+	if err != nil {
+		panic(err)
+	}
+	shooter.handle.SendPacket(fireResult)
+
+	opponentFired, err := json.Marshal(shared.OpponentFiredPacket{
+		PacketName: shared.OpponentFiredPacketName,
+		Position:   pos,
+		Hit:        hit,
+		Sunk:       sunkShip != nil,
+	})
+	if err != nil {
+		panic(err)
+	}
+	otherPlayer.handle.SendPacket(opponentFired)
+
+	// Das Ergebnis des letzten Schusses wird noch gesendet, bevor das Spiel endet
+	if hit && otherPlayer.board.isEmpty() {
+		i.party.EndGame(i.result(shooter))
+		return
+	}
+
+	// Wer trifft, darf noch einmal schießen
+	if hit {
+		i.turns.Again()
+	} else {
+		i.turns.Next()
+	}
+}
+
+func (i *impl) SkipTurn(game.Player) {
+	i.turns.Next()
+}
+
+func (i *impl) RandomMove(player game.Player) {
+	shooter := i.getPlayer(player)
+	i.fire(shooter, i.getOtherPlayer(shooter).board.randomTarget(i.party.Rand()))
+}
+
+func (i *impl) Forfeit(player game.Player) {
+	i.party.EndGame(i.result(i.getOtherPlayer(i.getPlayer(player))))
+}
+
+// Snapshot enthält die eigenen Schiffe des Spielers und die bisherigen Schüsse beider Spieler.
+// Zuschauer erhalten keinen Snapshot, da sie bei Schiffe versenken nichts sehen.
+func (i *impl) Snapshot(viewer game.Player) any {
+	viewerPlayer := i.getPlayer(viewer)
+	if viewerPlayer == nil {
 		return nil
-	default:
-		return fmt.Errorf("unknown packet name: %s", packetName)
 	}
+	otherPlayer := i.getOtherPlayer(viewerPlayer)
+
+	snapshot := shared.Snapshot{
+		Rules:         i.rules,
+		Ships:         viewerPlayer.ships,
+		Started:       i.gameStarted,
+		SetupTimeLeft: -1,
+	}
+	if setupTimeLeft, ok := i.setupTimeLeft(); ok {
+		snapshot.SetupTimeLeft = setupTimeLeft
+	}
+
+	if !i.gameStarted {
+		return snapshot
+	}
+
+	snapshot.Shots = otherPlayer.board.shots()
+	snapshot.SunkShips = otherPlayer.sunkShips()
+	for _, shot := range viewerPlayer.board.shots() {
+		snapshot.OpponentShots = append(snapshot.OpponentShots, shot.Position)
+	}
+	snapshot.Turn = i.turns.State()
+
+	return snapshot
 }
 
-func (i *impl) Tick() {}
+func (i *impl) HandlePaused() {}
+
+func (i *impl) HandleResumed() {
+	if i.turns != nil {
+		i.turns.Resume()
+		return
+	}
+	i.broadcastSetupTime()
+}
+
+func (i *impl) Tick() {
+	if i.turns != nil {
+		i.turns.Tick()
+		return
+	}
+	i.tickSetup()
+}
 
 func (i *impl) getPlayer(player game.Player) *player {
 	switch player {
@@ -216,7 +485,32 @@ func (i *impl) getPlayer(player game.Player) *player {
 	}
 }
 
+func (i *impl) getOtherPlayer(player *player) *player {
+	switch player {
+	case i.player1:
+		return i.player2
+	case i.player2:
+		return i.player1
+	default:
+		return nil
+	}
+}
+
+// checkOptions prüft, ob die gewählte Flotte auf das Spielfeld passt.
+func checkOptions(options protocol.OptionValues) error {
+	return shared.RulesFromOptions(options).Check()
+}
+
 var Type = game.Type{
-	Name:    shared.Name,
-	Creator: create,
+	Name:             shared.Name,
+	Creator:          create,
+	Bot:              createBot,
+	Description:      "Versenke alle Schiffe deines Gegners",
+	MinPlayers:       2,
+	MaxPlayers:       2,
+	SoloOpponent:     true,
+	PlaylistPairings: true,
+	Version:          shared.Version,
+	Options:          append(append(protocol.Options{}, shared.Options...), protocol.TurnOptions...),
+	CheckOptions:     checkOptions,
 }
@@ -0,0 +1,247 @@
+package schiffe_versenken
+
+import (
+	"math/rand"
+
+	shared "github.com/Lama06/Oinky-Party/schiffe_versenken"
+)
+
+// knowledge ist das, was der Bot über ein Feld des gegnerischen Spielfeldes weiß.
+type knowledge byte
+
+const (
+	unknownField knowledge = iota
+	waterField             // Hier wurde daneben geschossen oder hier kann kein Schiff liegen
+	hitField               // Hier wurde ein Schiff getroffen, das noch nicht versenkt ist
+	sunkField              // Hier liegt ein versenktes Schiff
+)
+
+// hitWeight gibt an, wie viel stärker eine mögliche Lage eines Schiffes in der Heatmap zählt, wenn sie ein bereits
+// getroffenes Feld enthält.
+const hitWeight = 10
+
+// targeting wählt die Felder, auf die der Bot schießt. Solange kein angeschossenes Schiff übrig ist, sucht der Bot
+// nach Schiffen (hunt), danach versucht er, das angeschossene Schiff zu versenken (target).
+type targeting struct {
+	rules     shared.Rules
+	fields    [][]knowledge // fields[x][y]
+	remaining shared.Fleet  // Die Schiffe, die noch nicht versenkt wurden
+}
+
+func newTargeting(rules shared.Rules) *targeting {
+	fields := make([][]knowledge, rules.Width)
+	for x := range fields {
+		fields[x] = make([]knowledge, rules.Height)
+	}
+
+	remaining := make(shared.Fleet, len(rules.Fleet))
+	for length, count := range rules.Fleet {
+		remaining[length] = count
+	}
+
+	return &targeting{
+		rules:     rules,
+		fields:    fields,
+		remaining: remaining,
+	}
+}
+
+func (t *targeting) field(pos shared.Position) knowledge {
+	return t.fields[pos.X][pos.Y]
+}
+
+func (t *targeting) handleFireResult(fireResult shared.FireResultPacket) {
+	pos := fireResult.Position
+	if !pos.Valid(t.rules) {
+		return
+	}
+
+	if !fireResult.Hit {
+		t.fields[pos.X][pos.Y] = waterField
+		return
+	}
+	t.fields[pos.X][pos.Y] = hitField
+
+	if !fireResult.Sunk || !fireResult.Ship.Valid(t.rules) {
+		return
+	}
+
+	// Da sich Schiffe nicht berühren dürfen, liegt um ein versenktes Schiff herum nur Wasser
+	for blocked := range fireResult.Ship.BlockedFields(t.rules) {
+		t.fields[blocked.X][blocked.Y] = waterField
+	}
+	for _, field := range fireResult.Ship {
+		t.fields[field.X][field.Y] = sunkField
+	}
+	if t.remaining[len(fireResult.Ship)] > 0 {
+		t.remaining[len(fireResult.Ship)]--
+	}
+}
+
+// hits gibt die getroffenen Felder von Schiffen zurück, die noch nicht versenkt wurden.
+func (t *targeting) hits() []shared.Position {
+	var hits []shared.Position
+	for x := range t.fields {
+		for y := range t.fields[x] {
+			if t.fields[x][y] == hitField {
+				hits = append(hits, shared.Position{X: x, Y: y})
+			}
+		}
+	}
+	return hits
+}
+
+// unknownFields gibt die Felder zurück, auf die noch geschossen werden kann. Ist parity größer als 1, werden nur die
+// Felder eines Schachbrettmusters zurückgegeben, da jedes Schiff mit der Länge parity mindestens eines davon berührt.
+func (t *targeting) unknownFields(parity int) []shared.Position {
+	var fields []shared.Position
+	for x := range t.fields {
+		for y := range t.fields[x] {
+			if t.fields[x][y] == unknownField && (parity <= 1 || (x+y)%parity == 0) {
+				fields = append(fields, shared.Position{X: x, Y: y})
+			}
+		}
+	}
+	return fields
+}
+
+// shortestRemaining gibt die Länge des kürzesten Schiffes zurück, das noch nicht versenkt wurde.
+func (t *targeting) shortestRemaining() int {
+	shortest := 0
+	for length, count := range t.remaining {
+		if count > 0 && (shortest == 0 || length < shortest) {
+			shortest = length
+		}
+	}
+	return shortest
+}
+
+// choose wählt das nächste Ziel abhängig von der Schwierigkeitsstufe:
+//   - Leicht schießt zufällig und danach neben angeschossene Schiffe.
+//   - Mittel schießt bei der Suche zufällig in einem Schachbrettmuster und versenkt angeschossene Schiffe mit der
+//     Heatmap.
+//   - Schwer verwendet immer die Heatmap.
+func (t *targeting) choose(difficulty int32, random *rand.Rand) shared.Position {
+	targetMode := len(t.hits()) != 0
+
+	var candidates []shared.Position
+	switch {
+	case difficulty == shared.EasyDifficulty && targetMode:
+		candidates = t.neighboursOfHits()
+	case difficulty == shared.EasyDifficulty:
+		candidates = t.unknownFields(1)
+	case difficulty == shared.MediumDifficulty && !targetMode:
+		candidates = t.unknownFields(t.shortestRemaining())
+	default:
+		candidates = t.bestFields(t.heatmap(targetMode))
+	}
+
+	if len(candidates) == 0 {
+		candidates = t.unknownFields(1)
+	}
+	if len(candidates) == 0 {
+		// Es gibt kein Feld mehr, auf das noch nicht geschossen wurde. Das kann nur passieren, wenn der Bot die
+		// Ergebnisse seiner Schüsse nicht kennt.
+		return shared.Position{X: random.Intn(t.rules.Width), Y: random.Intn(t.rules.Height)}
+	}
+	return candidates[random.Intn(len(candidates))]
+}
+
+// neighboursOfHits gibt die unbekannten Felder zurück, die waagerecht oder senkrecht neben einem Treffer liegen.
+func (t *targeting) neighboursOfHits() []shared.Position {
+	var neighbours []shared.Position
+	for _, hit := range t.hits() {
+		for _, offset := range []shared.Position{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}} {
+			neighbour := shared.Position{X: hit.X + offset.X, Y: hit.Y + offset.Y}
+			if neighbour.Valid(t.rules) && t.field(neighbour) == unknownField {
+				neighbours = append(neighbours, neighbour)
+			}
+		}
+	}
+	return neighbours
+}
+
+// heatmap zählt für jedes unbekannte Feld, wie viele Lagen der übrigen Schiffe es enthalten. Eine Lage ist nur möglich,
+// wenn sie kein Wasser und kein versenktes Schiff enthält und kein Treffer direkt neben ihr liegt, denn Schiffe dürfen
+// sich nicht berühren. Im target-Modus zählen nur Lagen, die getroffene Felder enthalten.
+func (t *targeting) heatmap(targetMode bool) [][]int {
+	heatmap := make([][]int, t.rules.Width)
+	for x := range heatmap {
+		heatmap[x] = make([]int, t.rules.Height)
+	}
+
+	for length, count := range t.remaining {
+		if count <= 0 {
+			continue
+		}
+
+		for _, horizontal := range []bool{true, false} {
+			if length == 1 && !horizontal {
+				continue // Sonst würden Schiffe mit nur einem Feld doppelt gezählt
+			}
+
+			for x := 0; x < t.rules.Width; x++ {
+				for y := 0; y < t.rules.Height; y++ {
+					ship := shared.NewShip(shared.Position{X: x, Y: y}, length, horizontal)
+					covered, ok := t.fits(ship)
+					if !ok || (targetMode && covered == 0) {
+						continue
+					}
+
+					weight := count * (1 + hitWeight*covered)
+					for _, pos := range ship {
+						if t.field(pos) == unknownField {
+							heatmap[pos.X][pos.Y] += weight
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return heatmap
+}
+
+// fits gibt an, ob ein Schiff in dieser Lage liegen kann und wie viele Treffer es enthält.
+func (t *targeting) fits(ship shared.Ship) (covered int, ok bool) {
+	if !ship.Valid(t.rules) {
+		return 0, false
+	}
+
+	inShip := make(map[shared.Position]struct{}, len(ship))
+	for _, pos := range ship {
+		switch t.field(pos) {
+		case waterField, sunkField:
+			return 0, false
+		case hitField:
+			covered++
+		}
+		inShip[pos] = struct{}{}
+	}
+
+	for blocked := range ship.BlockedFields(t.rules) {
+		if _, ok := inShip[blocked]; !ok && t.field(blocked) == hitField {
+			return 0, false
+		}
+	}
+
+	return covered, true
+}
+
+// bestFields gibt die Felder mit dem höchsten Wert in der Heatmap zurück.
+func (t *targeting) bestFields(heatmap [][]int) []shared.Position {
+	best := 0
+	var fields []shared.Position
+	for x := range heatmap {
+		for y, value := range heatmap[x] {
+			switch {
+			case value > best:
+				best = value
+				fields = []shared.Position{{X: x, Y: y}}
+			case value == best && value > 0:
+				fields = append(fields, shared.Position{X: x, Y: y})
+			}
+		}
+	}
+	return fields
+}
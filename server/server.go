@@ -3,30 +3,59 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"math/rand"
 	"net"
 	"time"
 
 	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
 )
 
 func StartServer() {
-	newServer().start()
+	var config config
+	flag.DurationVar(&config.partyGracePeriod, "party-grace-period", 0, "Wie lange leere Partys erhalten bleiben, bevor sie gelöscht werden")
+	flag.DurationVar(&config.sessionTimeout, "session-timeout", time.Minute, "Wie lange ein Spieler nach einem Verbindungsabbruch seine Sitzung fortsetzen kann")
+	flag.IntVar(&config.maxParties, "max-parties", 100, "Die maximale Anzahl an Partys")
+	flag.Int64Var(&config.gameSeed, "game-seed", 0, "Der Seed für den Zufall in allen Spielen, um ein Spiel genau wiederholen zu können. 0 bedeutet, dass jedes Spiel einen zufälligen Seed erhält.")
+	flag.StringVar(&config.replayDir, "replay-dir", "", "Der Ordner, in dem Aufzeichnungen der Spiele gespeichert werden. Ohne Ordner werden keine Spiele aufgezeichnet. Spiele ohne Zuschauer werden nie aufgezeichnet.")
+	flag.Parse()
+
+	newServer(config).start()
+}
+
+type config struct {
+	partyGracePeriod time.Duration
+	sessionTimeout   time.Duration
+	maxParties       int
+	replayDir        string
+	gameSeed         int64
 }
 
 type server struct {
+	config         config
 	players        players
 	parties        parties
+	playerIds      *idAllocator
+	partyIds       *idAllocator
+	sessions       map[string]*session // Die Sitzungen von Spielern, deren Verbindung abgebrochen ist
+	partyBrowsers  players             // Die Spieler, die über Änderungen an den Partys informiert werden wollen
+	matchmaking    map[string]*matchmakingQueue
 	newConnections chan net.Conn
 	disconnects    chan *player
 }
 
-func newServer() *server {
+func newServer(config config) *server {
 	return &server{
+		config:         config,
 		players:        map[int32]*player{},
 		parties:        map[int32]*party{},
+		playerIds:      newIdAllocator(),
+		partyIds:       newIdAllocator(),
+		sessions:       map[string]*session{},
+		partyBrowsers:  map[int32]*player{},
+		matchmaking:    map[string]*matchmakingQueue{},
 		newConnections: make(chan net.Conn, 100),
 		disconnects:    make(chan *player, 100),
 	}
@@ -61,6 +90,12 @@ func (s *server) start() {
 			party.tick()
 		}
 
+		s.tickMatchmaking()
+
+		s.removeAbandonedParties()
+
+		s.removeExpiredSessions()
+
 		<-ticker
 	}
 }
@@ -102,27 +137,104 @@ func (s *server) handleNewConnection(conn net.Conn) {
 
 	s.players[player.id] = player
 
-	welcome, err := json.Marshal(protocol.WelcomePacket{
-		PacketName: protocol.WelcomePacketName,
-		YourId:     player.id,
-		YourName:   player.name,
+	s.sendWelcome(player)
+
+	availableGames, err := json.Marshal(protocol.AvailableGamesPacket{
+		PacketName: protocol.AvailableGamesPacketName,
+		Games:      gameTypesToData(),
 	})
 	if err != nil {
 		panic(err)
 	}
-	player.SendPacket(welcome)
+	player.SendPacket(availableGames)
 
 	go player.forwardMessagesFromPlayer()
 	go player.forwardMessagesToPlayer()
 }
 
+func (s *server) sendWelcome(p *player) {
+	welcome, err := json.Marshal(protocol.WelcomePacket{
+		PacketName:   protocol.WelcomePacketName,
+		YourId:       p.id,
+		YourName:     p.name,
+		SessionToken: p.sessionToken,
+	})
+	if err != nil {
+		panic(err)
+	}
+	p.SendPacket(welcome)
+}
+
 func (s *server) handleDisconnect(p *player) {
+	s.unsubscribePartyBrowser(p)
+	s.leaveMatchmaking(p)
+
 	party := s.parties.byPlayer(p)
 	if party != nil {
 		party.removePlayer(p)
 	}
 
 	delete(s.players, p.id)
+
+	if s.config.sessionTimeout <= 0 {
+		s.playerIds.release(p.id)
+		return
+	}
+
+	// Die ID bleibt vergeben, bis die Sitzung abgelaufen ist
+	s.sessions[p.sessionToken] = &session{
+		id:      p.id,
+		name:    p.name,
+		party:   party,
+		expires: time.Now().Add(s.config.sessionTimeout),
+	}
+}
+
+func (s *server) removeExpiredSessions() {
+	now := time.Now()
+	for token, session := range s.sessions {
+		if now.After(session.expires) {
+			delete(s.sessions, token)
+			s.playerIds.release(session.id)
+		}
+	}
+}
+
+// resumeSession gibt dem Spieler die ID und den Namen aus seiner alten Sitzung und lässt ihn wieder seiner Party
+// beitreten.
+func (s *server) resumeSession(p *player, token string) error {
+	session, ok := s.sessions[token]
+	if !ok {
+		return errors.New("invalid or expired session token")
+	}
+
+	if s.parties.byPlayer(p) != nil {
+		return errors.New("player is already in a party")
+	}
+
+	delete(s.sessions, token)
+
+	s.unsubscribePartyBrowser(p)
+	s.leaveMatchmaking(p)
+
+	delete(s.players, p.id)
+	s.playerIds.release(p.id)
+
+	p.id = session.id
+	p.name = session.name
+	p.sessionToken = newSessionToken()
+	s.players[p.id] = p
+
+	s.sendWelcome(p)
+
+	// Die ID einer gelöschten Party kann inzwischen an eine andere Party vergeben worden sein
+	party := session.party
+	if party == nil || s.parties[party.id] != party || len(party.players) >= protocol.MaxPartySize || (party.currentGame != nil && !party.spectatable()) {
+		return nil
+	}
+	party.addPlayer(p)
+
+	return nil
 }
 
 func (s *server) handlePacket(sender *player, data []byte) error {
@@ -134,6 +246,17 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 	}
 
 	switch packetName {
+	case protocol.ResumeSessionPacketName:
+		var resumeSession protocol.ResumeSessionPacket
+		err := json.Unmarshal(data, &resumeSession)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		err = s.resumeSession(sender, resumeSession.SessionToken)
+		if err != nil {
+			return fmt.Errorf("failed to resume session: %w", err)
+		}
 	case protocol.ChangeNamePacketName:
 		var changeName protocol.ChangeNamePacket
 		err := json.Unmarshal(data, &changeName)
@@ -141,12 +264,7 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 			return fmt.Errorf("failed to unmarshal packet: %w", err)
 		}
 
-		currentParty := s.parties.byPlayer(sender)
-		if currentParty != nil {
-			return errors.New("cannot change name while in party")
-		}
-
-		sender.name = changeName.NewName
+		s.changeName(sender, changeName.NewName)
 	case protocol.QueryPartiesPacketName:
 		listParties, err := json.Marshal(protocol.ListPartiesPacket{
 			PacketName: protocol.ListPartiesPacketName,
@@ -156,6 +274,23 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 			panic(err)
 		}
 		sender.SendPacket(listParties)
+	case protocol.SubscribePartiesPacketName:
+		s.subscribePartyBrowser(sender)
+	case protocol.UnsubscribePartiesPacketName:
+		s.unsubscribePartyBrowser(sender)
+	case protocol.FindMatchPacketName:
+		var findMatch protocol.FindMatchPacket
+		err := json.Unmarshal(data, &findMatch)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		err = s.joinMatchmaking(sender, findMatch)
+		if err != nil {
+			return fmt.Errorf("failed to join matchmaking: %w", err)
+		}
+	case protocol.CancelFindMatchPacketName:
+		s.leaveMatchmaking(sender)
 	case protocol.CreatePartyPacketName:
 		var createParty protocol.CreatePartyPacket
 		err := json.Unmarshal(data, &createParty)
@@ -168,13 +303,16 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 			return fmt.Errorf("player is already in a party")
 		}
 
-		party := &party{
-			server:  s,
-			id:      rand.Int31(),
-			name:    createParty.Name,
-			players: map[int32]*player{},
+		if len(s.parties) >= s.config.maxParties {
+			return errors.New("the maximum number of parties has been reached")
 		}
+
+		s.unsubscribePartyBrowser(sender)
+		s.leaveMatchmaking(sender)
+
+		party := newParty(s, s.partyIds.allocate(), createParty.Name, sender)
 		s.parties[party.id] = party
+		s.notifyPartyCreated(party)
 
 		party.addPlayer(sender)
 	case protocol.JoinPartyPacketName:
@@ -194,10 +332,17 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 			return fmt.Errorf("failed to find party with id: %d", joinParty.Id)
 		}
 
-		if newParty.currentGame != nil {
-			return errors.New("a game is running in this party")
+		if newParty.currentGame != nil && !newParty.spectatable() {
+			return errors.New("a game without spectators is running in this party")
 		}
 
+		if len(newParty.players) >= protocol.MaxPartySize {
+			return errors.New("the party is full")
+		}
+
+		s.unsubscribePartyBrowser(sender)
+		s.leaveMatchmaking(sender)
+
 		newParty.addPlayer(sender)
 	case protocol.LeavePartyPacketName:
 		currentParty := s.parties.byPlayer(sender)
@@ -231,6 +376,142 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 		if err != nil {
 			return fmt.Errorf("failed to handle end game packet: %w", err)
 		}
+	case protocol.PauseGamePacketName:
+		var pauseGame protocol.PauseGamePacket
+		err := json.Unmarshal(data, &pauseGame)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty := s.parties.byPlayer(sender)
+		if currentParty == nil {
+			return errors.New("player is not in a party")
+		}
+
+		err = currentParty.handlePauseGamePacket(sender, pauseGame)
+		if err != nil {
+			return fmt.Errorf("failed to pause the game: %w", err)
+		}
+	case protocol.AddPlaylistEntryPacketName:
+		var addPlaylistEntry protocol.AddPlaylistEntryPacket
+		err := json.Unmarshal(data, &addPlaylistEntry)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.playlist.addEntry(addPlaylistEntry)
+		if err != nil {
+			return fmt.Errorf("failed to add playlist entry: %w", err)
+		}
+	case protocol.RemovePlaylistEntryPacketName:
+		var removePlaylistEntry protocol.RemovePlaylistEntryPacket
+		err := json.Unmarshal(data, &removePlaylistEntry)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.playlist.removeEntry(removePlaylistEntry)
+		if err != nil {
+			return fmt.Errorf("failed to remove playlist entry: %w", err)
+		}
+	case protocol.SetTeamPacketName:
+		var setTeam protocol.SetTeamPacket
+		err := json.Unmarshal(data, &setTeam)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.handleSetTeamPacket(setTeam)
+		if err != nil {
+			return fmt.Errorf("failed to set team: %w", err)
+		}
+	case protocol.BalanceTeamsPacketName:
+		var balanceTeams protocol.BalanceTeamsPacket
+		err := json.Unmarshal(data, &balanceTeams)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.handleBalanceTeamsPacket(balanceTeams)
+		if err != nil {
+			return fmt.Errorf("failed to balance teams: %w", err)
+		}
+	case protocol.AddBotPacketName:
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.handleAddBotPacket()
+		if err != nil {
+			return fmt.Errorf("failed to add bot: %w", err)
+		}
+	case protocol.RemoveBotPacketName:
+		var removeBot protocol.RemoveBotPacket
+		err := json.Unmarshal(data, &removeBot)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal packet: %w", err)
+		}
+
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.handleRemoveBotPacket(removeBot)
+		if err != nil {
+			return fmt.Errorf("failed to remove bot: %w", err)
+		}
+	case protocol.RematchPacketName:
+		currentParty := s.parties.byPlayer(sender)
+		if currentParty == nil {
+			return errors.New("player is not in a party")
+		}
+
+		err := currentParty.handleRematchPacket(sender)
+		if err != nil {
+			return fmt.Errorf("failed to vote for a rematch: %w", err)
+		}
+	case protocol.StartPlaylistPacketName:
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.playlist.start()
+		if err != nil {
+			return fmt.Errorf("failed to start the playlist: %w", err)
+		}
+	case protocol.StopPlaylistPacketName:
+		currentParty, err := s.partyHostedBy(sender)
+		if err != nil {
+			return err
+		}
+
+		err = currentParty.playlist.stop()
+		if err != nil {
+			return fmt.Errorf("failed to stop the playlist: %w", err)
+		}
 	default:
 		currentParty := s.parties.byPlayer(sender)
 		if currentParty == nil {
@@ -244,3 +525,61 @@ func (s *server) handlePacket(sender *player, data []byte) error {
 
 	return nil
 }
+
+// handlePartyEmpty wird aufgerufen, nachdem der letzte Spieler eine Party verlassen hat.
+// Die Party wird erst nach der Schonfrist gelöscht, damit die Spieler ihr wieder beitreten können.
+func (s *server) handlePartyEmpty(p *party) {
+	if s.config.partyGracePeriod <= 0 {
+		s.removeParty(p)
+		return
+	}
+
+	p.emptySince = time.Now()
+	s.notifyPartyUpdated(p)
+}
+
+func (s *server) removeAbandonedParties() {
+	for _, party := range s.parties {
+		if len(party.players) != 0 || party.emptySince.IsZero() {
+			continue
+		}
+
+		if time.Since(party.emptySince) >= s.config.partyGracePeriod {
+			s.removeParty(party)
+		}
+	}
+}
+
+func (s *server) removeParty(p *party) {
+	if p.currentGame != nil {
+		p.EndGame(game.AbortedResult)
+	}
+
+	delete(s.parties, p.id)
+	s.partyIds.release(p.id)
+	s.notifyPartyRemoved(p)
+
+	log.Printf("removed party %s(%d)\n", p.name, p.id)
+}
+
+// newGameSeed gibt den Seed für den Zufallsgenerator eines neuen Spieles zurück.
+func (s *server) newGameSeed() int64 {
+	if s.config.gameSeed != 0 {
+		return s.config.gameSeed
+	}
+	return time.Now().UnixNano()
+}
+
+// partyHostedBy gibt die Party zurück, in der sich der Spieler befindet, wenn er ihr Host ist.
+func (s *server) partyHostedBy(player *player) (*party, error) {
+	currentParty := s.parties.byPlayer(player)
+	if currentParty == nil {
+		return nil, errors.New("player is not in a party")
+	}
+
+	if !currentParty.isHost(player) {
+		return nil, errors.New("player is not the host of the party")
+	}
+
+	return currentParty, nil
+}
@@ -0,0 +1,216 @@
+// Package turns stellt die Reihenfolge der Züge in rundenbasierten Spielen bereit und begrenzt die Bedenkzeit der
+// Spieler.
+package turns
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Lama06/Oinky-Party/protocol"
+	"github.com/Lama06/Oinky-Party/server/game"
+)
+
+// Handler wird vom Spiel implementiert und entscheidet, was passiert, wenn die Zeit eines Spielers abläuft.
+type Handler interface {
+	// SkipTurn lässt den Spieler aussetzen. Das Spiel entscheidet, wer danach an der Reihe ist, zum Beispiel, weil
+	// ein Spieler keinen Zug machen kann oder das Spiel dadurch endet.
+	SkipTurn(player game.Player)
+
+	// RandomMove macht einen zufälligen, gültigen Zug für den Spieler.
+	RandomMove(player game.Player)
+
+	// Forfeit beendet das Spiel, weil der Spieler aufgegeben hat.
+	Forfeit(player game.Player)
+}
+
+type Config struct {
+	TurnTime      time.Duration // 0 bedeutet, dass es keine Begrenzung gibt
+	TotalTime     time.Duration // 0 bedeutet, dass es keine Begrenzung gibt
+	TimeoutAction protocol.TimeoutAction
+}
+
+// ConfigFromOptions liest die Einstellungen aus protocol.TurnOptions.
+func ConfigFromOptions(options protocol.OptionValues) Config {
+	return Config{
+		TurnTime:      protocol.TurnTime(options),
+		TotalTime:     protocol.TotalTime(options),
+		TimeoutAction: protocol.GetTimeoutAction(options),
+	}
+}
+
+func durationToTicks(d time.Duration) int {
+	return int(d / (protocol.TickSpeed * time.Millisecond))
+}
+
+func ticksToMilliseconds(ticks int) int32 {
+	return int32(ticks * protocol.TickSpeed)
+}
+
+// Order verwaltet, welcher Spieler an der Reihe ist. Die Spieler sind in der Reihenfolge an der Reihe, in der sie
+// an NewOrder übergeben wurden.
+type Order struct {
+	party          game.Party
+	handler        Handler
+	config         Config
+	players        []game.Player
+	current        int
+	turnTicksLeft  int
+	totalTicksLeft map[int32]int
+}
+
+func NewOrder(party game.Party, handler Handler, config Config, players []game.Player) *Order {
+	totalTicksLeft := make(map[int32]int, len(players))
+	for _, player := range players {
+		totalTicksLeft[player.Id()] = durationToTicks(config.TotalTime)
+	}
+
+	return &Order{
+		party:          party,
+		handler:        handler,
+		config:         config,
+		players:        players,
+		totalTicksLeft: totalTicksLeft,
+	}
+}
+
+// Start teilt den Spielern mit, wer als erstes an der Reihe ist.
+func (o *Order) Start() {
+	o.startTurn()
+}
+
+func (o *Order) Current() game.Player {
+	return o.players[o.current]
+}
+
+// CheckTurn gibt einen Fehler zurück, wenn der Spieler nicht an der Reihe ist.
+func (o *Order) CheckTurn(player game.Player) error {
+	if len(o.players) == 0 || player != o.Current() {
+		return errors.New("its not this players turn")
+	}
+	return nil
+}
+
+// Next lässt den nächsten Spieler an die Reihe kommen.
+func (o *Order) Next() {
+	o.current = (o.current + 1) % len(o.players)
+	o.startTurn()
+}
+
+// Again lässt denselben Spieler noch einmal an die Reihe kommen. Die Zeit für den Zug beginnt von vorne.
+func (o *Order) Again() {
+	o.startTurn()
+}
+
+// Remove entfernt einen Spieler, der das Spiel verlassen hat, aus der Reihenfolge.
+func (o *Order) Remove(player game.Player) {
+	for i, p := range o.players {
+		if p != player {
+			continue
+		}
+
+		wasCurrent := i == o.current
+		o.players = append(o.players[:i], o.players[i+1:]...)
+		delete(o.totalTicksLeft, player.Id())
+
+		if len(o.players) == 0 {
+			return
+		}
+
+		if i < o.current {
+			o.current--
+		}
+		if o.current >= len(o.players) {
+			o.current = 0
+		}
+		if wasCurrent {
+			o.startTurn()
+		}
+		return
+	}
+}
+
+// Resume teilt den Spielern nach einer Pause erneut mit, wer an der Reihe ist und wie viel Zeit ihm noch bleibt.
+func (o *Order) Resume() {
+	if len(o.players) == 0 {
+		return
+	}
+
+	turnChanged, err := json.Marshal(o.State())
+	if err != nil {
+		panic(err)
+	}
+	o.party.BroadcastPacket(turnChanged)
+}
+
+func (o *Order) startTurn() {
+	o.turnTicksLeft = durationToTicks(o.config.TurnTime)
+
+	turnChanged, err := json.Marshal(o.State())
+	if err != nil {
+		panic(err)
+	}
+	o.party.BroadcastPacket(turnChanged)
+}
+
+// State beschreibt, wer an der Reihe ist und wie viel Zeit den Spielern noch bleibt. Spiele können den Zustand in
+// ihre Snapshots aufnehmen.
+func (o *Order) State() protocol.TurnChangedPacket {
+	turnTimeLeft := int32(-1)
+	if o.config.TurnTime != 0 {
+		turnTimeLeft = ticksToMilliseconds(o.turnTicksLeft)
+	}
+
+	var totalTimeLeft map[int32]int32
+	if o.config.TotalTime != 0 {
+		totalTimeLeft = make(map[int32]int32, len(o.totalTicksLeft))
+		for id, ticks := range o.totalTicksLeft {
+			totalTimeLeft[id] = ticksToMilliseconds(ticks)
+		}
+	}
+
+	return protocol.TurnChangedPacket{
+		PacketName:    protocol.TurnChangedPacketName,
+		Player:        o.Current().Id(),
+		TurnTimeLeft:  turnTimeLeft,
+		TotalTimeLeft: totalTimeLeft,
+	}
+}
+
+// Tick zieht die vergangene Zeit von der Bedenkzeit des Spielers ab, der an der Reihe ist.
+// Ist seine gesamte Bedenkzeit abgelaufen, verliert er. Ist nur die Zeit für den Zug abgelaufen, wird
+// Config.TimeoutAction ausgeführt.
+func (o *Order) Tick() {
+	if len(o.players) == 0 {
+		return
+	}
+	current := o.Current()
+
+	if o.config.TotalTime != 0 {
+		o.totalTicksLeft[current.Id()]--
+		if o.totalTicksLeft[current.Id()] <= 0 {
+			o.handler.Forfeit(current)
+			return
+		}
+	}
+
+	if o.config.TurnTime == 0 {
+		return
+	}
+
+	o.turnTicksLeft--
+	if o.turnTicksLeft > 0 {
+		return
+	}
+	// Falls der Spieler nach der Aktion noch an der Reihe ist, beginnt seine Zeit von vorne
+	o.turnTicksLeft = durationToTicks(o.config.TurnTime)
+
+	switch o.config.TimeoutAction {
+	case protocol.SkipTurnTimeoutAction:
+		o.handler.SkipTurn(current)
+	case protocol.RandomMoveTimeoutAction:
+		o.handler.RandomMove(current)
+	case protocol.ForfeitTimeoutAction:
+		o.handler.Forfeit(current)
+	}
+}